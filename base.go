@@ -2,9 +2,15 @@ package errors
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"regexp"
+	"runtime"
 	"sync"
+	"sync/atomic"
+
+	"github.com/olekukonko/errors/internal/errstate"
 )
 
 // Constants defining default configuration and context keys.
@@ -12,29 +18,104 @@ const (
 	ctxTimeout = "[error] timeout" // Context key marking timeout errors.
 	ctxRetry   = "[error] retry"   // Context key marking retryable errors.
 
+	ctxTransient = "[error] transient" // Context key marking transient errors.
+
+	ctxRequestID = "request_id" // Context key holding the request correlation ID.
+	ctxStep      = "step"       // Context key holding the name of a failed Chain step.
+	ctxCaller    = "caller"     // Context key holding the creation site recorded by WithCaller.
+	ctxExitCode  = "exit_code"  // Context key holding the process exit code set by WithExitCode.
+	ctxGoroutine = "goroutine"  // Context key holding the creating goroutine's ID (Config.CaptureGoroutineID).
+
 	contextSize = 4   // Initial size of fixed-size context array for small contexts.
 	bufferSize  = 256 // Initial buffer size for JSON marshaling.
 	warmUpSize  = 100 // Number of errors to pre-warm the pool for efficiency.
 	stackDepth  = 32  // Maximum stack trace depth to prevent excessive memory use.
 
+	messageSeparator = ": "           // Default separator between an error's message and its cause.
+	truncatedMarker  = "…(truncated)" // Suffix of context values cut by Config.MaxJSONValueLen.
+	scrubbedMarker   = "[REDACTED]"   // Replacement for text matched by Scrub.
+
 	DefaultCode = 500 // Default HTTP status code for errors if not specified.
 )
 
 // spaceRe is a precompiled regex for normalizing whitespace in error messages.
 var spaceRe = regexp.MustCompile(`\s+`)
 
+// DefaultScrubPatterns are the patterns Scrub uses when called without any:
+// email addresses, credit card numbers, and US social security numbers.
+// Replace or extend the slice at startup to change the default.
+var DefaultScrubPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+	regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+}
+
 // jsonBufferPool manages reusable buffers for JSON marshaling to reduce allocations.
+// jsonEncoderPool manages reusable encoders for streaming JSON to an io.Writer.
 var (
 	jsonBufferPool = sync.Pool{
 		New: func() interface{} {
 			return bytes.NewBuffer(make([]byte, 0, bufferSize))
 		},
 	}
+	jsonEncoderPool = sync.Pool{
+		New: func() interface{} {
+			s := &jsonStreamEncoder{}
+			s.enc = json.NewEncoder(s)
+			s.enc.SetEscapeHTML(false)
+			return s
+		},
+	}
 )
 
+// jsonStreamEncoder pairs a json.Encoder with a retargetable writer, so one
+// encoder can be pooled and pointed at a different destination on each use.
+type jsonStreamEncoder struct {
+	w   io.Writer
+	enc *json.Encoder
+}
+
+// Write forwards p to the current destination writer.
+func (s *jsonStreamEncoder) Write(p []byte) (int, error) {
+	return s.w.Write(p)
+}
+
 // ErrorCategory is a string type for categorizing errors (e.g., "network", "validation").
 type ErrorCategory string
 
+// Severity ranks how serious an error is, from SeverityInfo to SeverityFatal.
+// The zero value, SeverityNone, means no severity was set.
+type Severity int
+
+// Severity levels, in increasing order of seriousness.
+const (
+	SeverityNone Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+	SeverityFatal
+)
+
+// String returns the lowercase name of the severity, e.g. "fatal".
+func (s Severity) String() string {
+	switch s {
+	case SeverityNone:
+		return "none"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	case SeverityFatal:
+		return "fatal"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
 // ErrorOpts provides options for customizing error creation.
 type ErrorOpts struct {
 	SkipStack int // Number of stack frames to skip when capturing the stack trace.
@@ -48,6 +129,38 @@ type Config struct {
 	DisablePooling bool // If true, disables object pooling for errors.
 	FilterInternal bool // If true, filters internal package frames from stack traces.
 	AutoFree       bool // If true, automatically returns errors to pool when GC collects them.
+	MaxPoolSize    int  // Maximum errors retained by the pool; 0 means unlimited.
+
+	// MaxWrapDepth caps the cause chain length built by the Wrap, Wrapf,
+	// WrapNotNil, WrapMany and ReplaceCause methods and the package-level Wrap
+	// and Wrapf; 0 means unlimited. A cause that would exceed it is dropped
+	// whole, so the new error loses the entire chain, root cause included.
+	// Chains built by Newf with %w, Copy, decoding and sentinels are not
+	// checked.
+	MaxWrapDepth int
+
+	// CaptureGoroutineID records the ID of the goroutine creating each error in
+	// its context under "goroutine", shown by Format and JSON output. Costs
+	// about a microsecond per error, so enable it only while debugging.
+	CaptureGoroutineID bool
+
+	// MaxJSONValueLen, if positive, caps the length in bytes of string-like
+	// context values (strings, []byte, errors, fmt.Stringers) in JSON output;
+	// longer values are cut and end with "…(truncated)".
+	MaxJSONValueLen int
+
+	// HeuristicClassification makes IsTimeout and IsRetryable also match errors
+	// whose message contains "timeout" or "retry". Off by default, since such
+	// substrings misclassify messages like "no timeout configured".
+	HeuristicClassification bool
+
+	// MessageSeparator joins an error's message and its cause's in Error();
+	// empty uses the default ": ". Messages built by Newf with %w are unaffected.
+	MessageSeparator string
+
+	// StackFilter, if set, decides per frame whether Stack and FastStack keep it
+	// (true keeps the frame), replacing FilterInternal's built-in rules.
+	StackFilter func(runtime.Frame) bool
 }
 
 // cachedConfig holds the current configuration, updated only by Configure().
@@ -58,6 +171,13 @@ type cachedConfig struct {
 	disablePooling bool
 	filterInternal bool
 	autoFree       bool
+	maxPoolSize    int
+	maxWrapDepth   int
+	maxJSONLen     int
+	goroutineID    bool
+	stackFilter    func(runtime.Frame) bool
+	separator      string
+	heuristics     bool
 }
 
 var (
@@ -97,8 +217,14 @@ func init() {
 		disablePooling: false,
 		filterInternal: true,
 		autoFree:       false, // opt-in; explicit Free() is the safe default
+		separator:      messageSeparator,
 	}
 	WarmPool(warmUpSize) // Pre-allocate errors for performance.
+	errstate.SetCount = func(err error, count uint64) {
+		if e, ok := err.(*Error); ok && e != nil {
+			atomic.StoreUint64(&e.count, count)
+		}
+	}
 }
 
 // Configure updates the global configuration for the errors package.
@@ -120,11 +246,50 @@ func Configure(cfg Config) {
 	currentConfig.disablePooling = cfg.DisablePooling
 	currentConfig.filterInternal = cfg.FilterInternal
 	currentConfig.autoFree = cfg.AutoFree
+	currentConfig.maxPoolSize = cfg.MaxPoolSize
+	currentConfig.maxWrapDepth = cfg.MaxWrapDepth
+	currentConfig.maxJSONLen = cfg.MaxJSONValueLen
+	currentConfig.goroutineID = cfg.CaptureGoroutineID
+	currentConfig.stackFilter = cfg.StackFilter
+	currentConfig.heuristics = cfg.HeuristicClassification
+	currentConfig.separator = cfg.MessageSeparator
+	if currentConfig.separator == "" {
+		currentConfig.separator = messageSeparator
+	}
+}
+
+// ConfigureWithValidation checks cfg and applies it like Configure, or returns
+// an error describing every invalid field and leaves the configuration unchanged.
+// Zero values keep their Configure meaning (e.g. StackDepth 0 keeps the current depth).
+// Example:
+//
+//	if err := errors.ConfigureWithValidation(cfg); err != nil {
+//	  log.Fatal(err)
+//	}
+func ConfigureWithValidation(cfg Config) error {
+	var problems []error
+	if cfg.StackDepth < 0 {
+		problems = append(problems, Newf("errors: invalid Config.StackDepth %d: must not be negative", cfg.StackDepth))
+	}
+	if cfg.ContextSize < 0 {
+		problems = append(problems, Newf("errors: invalid Config.ContextSize %d: must not be negative", cfg.ContextSize))
+	}
+	if cfg.MaxPoolSize < 0 {
+		problems = append(problems, Newf("errors: invalid Config.MaxPoolSize %d: must not be negative", cfg.MaxPoolSize))
+	}
+	if cfg.MaxWrapDepth < 0 {
+		problems = append(problems, Newf("errors: invalid Config.MaxWrapDepth %d: must not be negative", cfg.MaxWrapDepth))
+	}
+	if err := Join(problems...); err != nil {
+		return err
+	}
+	Configure(cfg)
+	return nil
 }
 
 // WarmPool pre-populates the error pool with count instances.
 // Improves performance by reducing initial allocations.
-// No-op if pooling is disabled.
+// No-op if pooling is disabled; instances beyond Config.MaxPoolSize are discarded.
 // Example:
 //
 //	errors.WarmPool(1000)
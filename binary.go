@@ -0,0 +1,301 @@
+// Compact binary encoding of *Error for caches and message queues.
+//
+// Layout (version 1), with strings as uvarint length + bytes:
+//
+//	version byte
+//	flags   byte    (bit 0: code set, bit 1: message built by Newf with %w)
+//	name, msg, template, category, cause  strings (cause is its Error() text, "" if none)
+//	code, severity                        varints
+//	context count, then per item: key string, type tag byte, value
+//	stack count, then per frame: string
+//
+// Context values of primitive types keep their type (other integer widths
+// become int64/uint64, float32 becomes float64); anything else is stored
+// as its fmt.Sprint text.
+
+package errors
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+const binaryVersion = 1
+
+// Flag bits of the binary encoding.
+const (
+	binaryHasCode byte = 1 << iota
+	binaryFormatWrapped
+)
+
+// Type tags for binary-encoded context values.
+const (
+	binaryNil byte = iota
+	binaryString
+	binaryInt
+	binaryInt64
+	binaryUint64
+	binaryFloat64
+	binaryBool
+)
+
+// MarshalBinary encodes the error’s name, message, template, category, code,
+// severity, context, stack frames, and cause text in a compact binary form,
+// implementing encoding.BinaryMarshaler. It is smaller and faster than JSON.
+// The stack is stored as rendered by Stack(). The cause chain is not kept: only
+// the cause's Error() text is stored, so its code, name, context, and any causes
+// below it are lost, and UnmarshalBinary restores it as a plain message.
+// Example:
+//
+//	data, _ := err.MarshalBinary()
+//	cache.Set(key, data)
+func (e *Error) MarshalBinary() ([]byte, error) {
+	if e == nil {
+		return nil, New("errors: MarshalBinary on nil *Error")
+	}
+	var flags byte
+	if e.hasCode {
+		flags |= binaryHasCode
+	}
+	if e.formatWrapped {
+		flags |= binaryFormatWrapped
+	}
+	cause := ""
+	if e.cause != nil {
+		cause = e.cause.Error()
+	}
+
+	buf := make([]byte, 0, bufferSize)
+	buf = append(buf, binaryVersion, flags)
+	for _, s := range [...]string{e.name, e.msg, e.template, e.category, cause} {
+		buf = appendBinaryString(buf, s)
+	}
+	buf = binary.AppendVarint(buf, int64(e.code))
+	buf = binary.AppendVarint(buf, int64(e.severity))
+
+	keys := e.ContextKeys()
+	buf = binary.AppendUvarint(buf, uint64(len(keys)))
+	for _, key := range keys {
+		v, _ := e.contextValue(key)
+		buf = appendBinaryString(buf, key)
+		buf = appendBinaryValue(buf, v)
+	}
+
+	stack := e.Stack()
+	buf = binary.AppendUvarint(buf, uint64(len(stack)))
+	for _, frame := range stack {
+		buf = appendBinaryString(buf, frame)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes data produced by MarshalBinary into e, replacing its
+// contents, and implements encoding.BinaryUnmarshaler. The stack is restored as
+// with SetStackStrings and a non-empty cause text becomes a plain *Error cause.
+// Data with bytes left over after the encoded error is rejected.
+// Example:
+//
+//	var err errors.Error
+//	if decodeErr := err.UnmarshalBinary(data); decodeErr != nil {
+//	  return decodeErr
+//	}
+func (e *Error) UnmarshalBinary(data []byte) error {
+	if e == nil {
+		return New("errors: UnmarshalBinary into nil *Error")
+	}
+	if e.IsFrozen() {
+		return New("errors: UnmarshalBinary into frozen *Error")
+	}
+	if len(data) < 2 {
+		return New("errors: binary data too short")
+	}
+	if data[0] != binaryVersion {
+		return Newf("errors: unsupported binary version %d", data[0])
+	}
+	flags := data[1]
+	d := binaryDecoder{data: data[2:]}
+
+	var fields [5]string
+	for i := range fields {
+		fields[i] = d.string()
+	}
+	code := d.varint()
+	severity := d.varint()
+
+	n := d.uvarint()
+	if n > uint64(len(d.data)) { // Each item takes at least two bytes.
+		return New("errors: invalid binary context count")
+	}
+	ctx := make([]contextItem, 0, n)
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		key := d.string()
+		ctx = append(ctx, contextItem{key: key, value: d.value()})
+	}
+
+	n = d.uvarint()
+	if n > uint64(len(d.data)) {
+		return New("errors: invalid binary stack count")
+	}
+	var stack []string
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		stack = append(stack, d.string())
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.data) != 0 {
+		return Newf("errors: %d trailing bytes after binary error", len(d.data))
+	}
+
+	e.Reset()
+	e.name, e.msg, e.template, e.category = fields[0], fields[1], fields[2], fields[3]
+	if fields[4] != "" {
+		e.cause = New(fields[4])
+	}
+	e.code = int32(code)
+	e.hasCode = flags&binaryHasCode != 0
+	e.formatWrapped = flags&binaryFormatWrapped != 0
+	e.severity = Severity(severity)
+	for _, item := range ctx {
+		e.With(item.key, item.value)
+	}
+	e.stackStrings = stack
+	return nil
+}
+
+// appendBinaryString appends s with a uvarint length prefix.
+func appendBinaryString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// appendBinaryValue appends a tagged context value.
+func appendBinaryValue(buf []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(buf, binaryNil)
+	case string:
+		return appendBinaryString(append(buf, binaryString), x)
+	case int:
+		return binary.AppendVarint(append(buf, binaryInt), int64(x))
+	case int8:
+		return binary.AppendVarint(append(buf, binaryInt64), int64(x))
+	case int16:
+		return binary.AppendVarint(append(buf, binaryInt64), int64(x))
+	case int32:
+		return binary.AppendVarint(append(buf, binaryInt64), int64(x))
+	case int64:
+		return binary.AppendVarint(append(buf, binaryInt64), x)
+	case uint:
+		return binary.AppendUvarint(append(buf, binaryUint64), uint64(x))
+	case uint8:
+		return binary.AppendUvarint(append(buf, binaryUint64), uint64(x))
+	case uint16:
+		return binary.AppendUvarint(append(buf, binaryUint64), uint64(x))
+	case uint32:
+		return binary.AppendUvarint(append(buf, binaryUint64), uint64(x))
+	case uint64:
+		return binary.AppendUvarint(append(buf, binaryUint64), x)
+	case float32:
+		return binary.LittleEndian.AppendUint64(append(buf, binaryFloat64), math.Float64bits(float64(x)))
+	case float64:
+		return binary.LittleEndian.AppendUint64(append(buf, binaryFloat64), math.Float64bits(x))
+	case bool:
+		if x {
+			return append(buf, binaryBool, 1)
+		}
+		return append(buf, binaryBool, 0)
+	default:
+		return appendBinaryString(append(buf, binaryString), fmt.Sprint(x))
+	}
+}
+
+// binaryDecoder reads the binary encoding, recording the first error;
+// once err is set every read returns a zero value.
+type binaryDecoder struct {
+	data []byte
+	err  error
+}
+
+// fail records a truncated or malformed input.
+func (d *binaryDecoder) fail() {
+	if d.err == nil {
+		d.err = New("errors: truncated or malformed binary data")
+	}
+	d.data = nil
+}
+
+// uvarint reads an unsigned varint.
+func (d *binaryDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// varint reads a signed varint.
+func (d *binaryDecoder) varint() int64 {
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// byte reads a single byte.
+func (d *binaryDecoder) byte() byte {
+	if len(d.data) < 1 {
+		d.fail()
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+// string reads a length-prefixed string.
+func (d *binaryDecoder) string() string {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		d.fail()
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+// value reads a tagged context value.
+func (d *binaryDecoder) value() interface{} {
+	switch tag := d.byte(); tag {
+	case binaryNil:
+		return nil
+	case binaryString:
+		return d.string()
+	case binaryInt:
+		return int(d.varint())
+	case binaryInt64:
+		return d.varint()
+	case binaryUint64:
+		return d.uvarint()
+	case binaryFloat64:
+		if len(d.data) < 8 {
+			d.fail()
+			return nil
+		}
+		bits := binary.LittleEndian.Uint64(d.data)
+		d.data = d.data[8:]
+		return math.Float64frombits(bits)
+	case binaryBool:
+		return d.byte() != 0
+	default:
+		d.fail()
+		return nil
+	}
+}
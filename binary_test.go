@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"encoding"
+	"reflect"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Error)(nil)
+	_ encoding.BinaryUnmarshaler = (*Error)(nil)
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	orig := Named("DBError").
+		Msgf("query failed").
+		WithCode(503).
+		WithCategory("database").
+		WithSeverity(SeverityCritical).
+		With("table", "users", "rows", 3, "retry", true, "latency", 1.5, "id", int64(-7), "size", uint32(9), "nothing", nil).
+		Wrap(New("connection reset"))
+	defer orig.Free()
+
+	data, err := orig.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error: %v", err)
+	}
+	var got Error
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error: %v", err)
+	}
+
+	if got.Name() != "DBError" || got.Error() != orig.Error() {
+		t.Errorf("round trip name/message = %q/%q, want %q/%q", got.Name(), got.Error(), "DBError", orig.Error())
+	}
+	if got.Code() != 503 || got.Category() != "database" || got.Severity() != SeverityCritical {
+		t.Errorf("round trip code/category/severity = %d/%q/%v", got.Code(), got.Category(), got.Severity())
+	}
+	want := map[string]interface{}{
+		"table": "users", "rows": 3, "retry": true, "latency": 1.5,
+		"id": int64(-7), "size": uint64(9), "nothing": nil,
+	}
+	if !reflect.DeepEqual(got.Context(), want) {
+		t.Errorf("round trip context = %v, want %v", got.Context(), want)
+	}
+	if !reflect.DeepEqual(got.Stack(), orig.Stack()) {
+		t.Errorf("round trip stack = %v, want %v", got.Stack(), orig.Stack())
+	}
+
+	wrapped := Newf("read: %w", New("EOF")).WithCode(0)
+	defer wrapped.Free()
+	data, _ = wrapped.MarshalBinary()
+	var gotWrapped Error
+	if err := gotWrapped.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error: %v", err)
+	}
+	if gotWrapped.Error() != "read: EOF" || gotWrapped.CodeOrDefault(500) != 0 {
+		t.Errorf("round trip of Newf error = %q (code %d)", gotWrapped.Error(), gotWrapped.CodeOrDefault(500))
+	}
+
+	coded := New("outer").Wrap(Named("NotFound").WithCode(404))
+	defer coded.Free()
+	data, _ = coded.MarshalBinary()
+	var gotCoded Error
+	if err := gotCoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error: %v", err)
+	}
+	if cause, ok := gotCoded.Unwrap().(*Error); !ok || cause.Error() != "NotFound" || cause.CodeChain() != 0 {
+		t.Errorf("round trip cause = %#v, want the text only", gotCoded.Unwrap())
+	}
+}
+
+func TestBinaryUnmarshalInvalid(t *testing.T) {
+	data, _ := New("x").With("k", "v").MarshalBinary()
+	for i := 0; i < len(data); i++ {
+		var e Error
+		if err := e.UnmarshalBinary(data[:i]); err == nil {
+			t.Errorf("UnmarshalBinary() of %d/%d bytes should fail", i, len(data))
+		}
+	}
+	var e Error
+	if err := e.UnmarshalBinary([]byte{99, 0}); err == nil {
+		t.Error("UnmarshalBinary() should reject unknown versions")
+	}
+	if err := e.UnmarshalBinary(append(data, data...)); err == nil {
+		t.Error("UnmarshalBinary() should reject trailing bytes")
+	}
+}
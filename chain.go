@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,38 +20,82 @@ type Chain struct {
 	lastStep   *chainStep         // Pointer to the last added step for configuration
 	logHandler slog.Handler       // Optional logging handler (nil means no logging)
 	cancel     context.CancelFunc // Function to cancel the context
-	runCtx     context.Context    // Active context for Run/RunAll; shared with StepCtx closures
 	configMu   sync.RWMutex       // Protects chainConfig against concurrent Timeout() calls
+	asyncLog   *asyncLogger       // Background log queue (nil means synchronous logging)
+	metrics    MetricsSink        // Receives per-step failure counts (nil disables metrics)
+	setupErrs  []error            // Builder misuse recorded in lenient mode, reported by Run and RunAll
+	progressMu sync.Mutex         // Protects progress, which Snapshot reads from other goroutines
+	progress   ChainSnapshot      // Progress of the current or last run
+}
+
+// ChainSnapshot is a point-in-time view of a chain's progress; see Chain.Snapshot.
+type ChainSnapshot struct {
+	Running   bool   // Whether Run or RunAll is executing
+	Step      int    // Index of the executing step, or -1 if none
+	StepName  string // Name of the executing step, if set with Name
+	Completed int    // Steps executed so far, failed ones included
+	Errors    int    // Errors collected so far
+	Total     int    // Number of steps in the run
 }
 
 // chainStep represents a single step in the chain.
 type chainStep struct {
-	execute  func() error // Function to execute for this step
-	optional bool         // If true, errors don't stop the chain
-	config   stepConfig   // Step-specific configuration
+	execute    func() error                    // Function to execute for this step
+	executeCtx func(ctx context.Context) error // Context-aware function set by StepCtx (used instead of execute)
+	optional   bool                            // If true, errors don't stop the chain
+	config     stepConfig                      // Step-specific configuration
+	result     *stepResult                     // Value slot of a step added by StepResult (nil otherwise)
+}
+
+// stepResult holds the value returned by the last successful run of a
+// StepResult step. It is shared by chains the step is copied into with Then.
+type stepResult struct {
+	mu    sync.Mutex
+	value interface{}
+	ok    bool
 }
 
 // chainConfig holds chain-wide settings.
 type chainConfig struct {
-	timeout   time.Duration // Maximum duration for the entire chain
-	maxErrors int           // Maximum number of errors before stopping (-1 for unlimited)
-	autoWrap  bool          // Whether to automatically wrap errors with additional context
+	timeout    time.Duration // Maximum duration for the entire chain
+	maxErrors  int           // Maximum number of errors before stopping (-1 for unlimited)
+	autoWrap   bool          // Whether to automatically wrap errors with additional context
+	timeoutErr func() *Error // Builds the error returned when the chain times out (nil uses default)
+	asyncBuf   int           // Buffer size for asynchronous logging (0 for synchronous)
+	retryOpts  []RetryOption // Chain-wide defaults applied before each step's Retry options
+	dedup      bool          // Whether RunAll skips errors whose message was already collected
+	lenient    bool          // Whether builder misuse is recorded as a setup error instead of panicking
 }
 
 // stepConfig holds configuration for an individual step.
 type stepConfig struct {
+	name         string                 // Step label for logs and the "step" error context key
 	context      map[string]interface{} // Arbitrary key-value pairs for context
 	category     ErrorCategory          // Category for error classification
 	code         int                    // Numeric error code
 	retry        *Retry                 // Retry policy for the step
 	logOnFail    bool                   // Whether to log errors automatically
-	metricsLabel string                 // Label for metrics (not used in this code)
+	metricsLabel string                 // Label for failure metrics reported to the MetricsSink
 	logAttrs     []slog.Attr            // Additional attributes for logging
 }
 
 // ChainOption defines a function that configures a Chain.
 type ChainOption func(*Chain)
 
+// MetricsSink receives failure counts for steps labeled with Chain.Metric.
+// Implementations must be safe for concurrent use and should not block.
+type MetricsSink interface {
+	IncFailure(label string)
+}
+
+// MetricsSinkFunc adapts an ordinary function to the MetricsSink interface.
+type MetricsSinkFunc func(label string)
+
+// IncFailure calls f(label).
+func (f MetricsSinkFunc) IncFailure(label string) {
+	f(label)
+}
+
 // NewChain creates a new Chain with the given options.
 // Logging is disabled by default (logHandler is nil).
 func NewChain(opts ...ChainOption) *Chain {
@@ -59,12 +104,17 @@ func NewChain(opts ...ChainOption) *Chain {
 			autoWrap:  true, // Enable error wrapping by default
 			maxErrors: -1,   // No limit on errors by default
 		},
+		progress: ChainSnapshot{Step: -1},
 		// logHandler is nil, meaning no logging unless explicitly configured
 	}
 	// Apply each configuration option
 	for _, opt := range opts {
 		opt(c)
 	}
+	// Start the background logger once the handler is known, regardless of option order
+	if c.config.asyncBuf > 0 && c.logHandler != nil {
+		c.asyncLog = newAsyncLogger(c.logHandler, c.config.asyncBuf)
+	}
 	return c
 }
 
@@ -76,6 +126,37 @@ func ChainWithLogHandler(handler slog.Handler) ChainOption {
 	}
 }
 
+// ChainWithAsyncLogging queues log records to a background goroutine instead
+// of calling the handler inline, keeping step execution fast under heavy logging.
+// Records are dropped (and counted, see DroppedLogs) when the buffer of bufSize
+// is full. Call Flush to wait for queued records and Close to stop the goroutine.
+// A value <= 0 keeps logging synchronous; has no effect without a log handler.
+func ChainWithAsyncLogging(bufSize int) ChainOption {
+	return func(c *Chain) {
+		if bufSize < 0 {
+			bufSize = 0
+		}
+		c.config.asyncBuf = bufSize
+	}
+}
+
+// ChainWithJitter enables or disables jitter for every step configured with Retry.
+// Disabling it makes retry delays deterministic, e.g. for timing-sensitive tests.
+// Options passed to an individual Retry call still take precedence.
+func ChainWithJitter(jitter bool) ChainOption {
+	return func(c *Chain) {
+		c.config.retryOpts = append(c.config.retryOpts, WithJitter(jitter))
+	}
+}
+
+// ChainWithMetrics sets the sink that receives a failure count for each
+// failing step labeled with Metric. If sink is nil, metrics are disabled.
+func ChainWithMetrics(sink MetricsSink) ChainOption {
+	return func(c *Chain) {
+		c.metrics = sink
+	}
+}
+
 // ChainWithTimeout sets a timeout for the entire chain.
 func ChainWithTimeout(d time.Duration) ChainOption {
 	return func(c *Chain) {
@@ -83,6 +164,17 @@ func ChainWithTimeout(d time.Duration) ChainOption {
 	}
 }
 
+// ChainWithTimeoutError sets a factory for the error returned when the chain's
+// timeout expires. The chain returns a copy of fn's error, so fn may return a
+// shared sentinel; the copy gets context.DeadlineExceeded as its cause if it has
+// none, so errors.Is checks keep working. If fn is nil or returns nil, the
+// default "chain timed out after X at step N" error is used.
+func ChainWithTimeoutError(fn func() *Error) ChainOption {
+	return func(c *Chain) {
+		c.config.timeoutErr = fn
+	}
+}
+
 // ChainWithMaxErrors sets the maximum number of errors allowed.
 // A value <= 0 means no limit.
 func ChainWithMaxErrors(max int) ChainOption {
@@ -95,6 +187,25 @@ func ChainWithMaxErrors(max int) ChainOption {
 	}
 }
 
+// ChainDedupErrors makes RunAll skip a failing step's error, including its log
+// entry, when an error with the same message was already collected. Errors then
+// lists each distinct failure once; metrics still count every failure.
+func ChainDedupErrors() ChainOption {
+	return func(c *Chain) {
+		c.config.dedup = true
+	}
+}
+
+// ChainLenient makes builder methods such as Step, WithLog, Tag, and Code record
+// misuse (a nil function, or configuring before any step) as a setup error
+// instead of panicking, for chains assembled from configuration. Run and RunAll
+// then return the setup errors, also listed by Errors, without running any step.
+func ChainLenient() ChainOption {
+	return func(c *Chain) {
+		c.config.lenient = true
+	}
+}
+
 // ChainWithAutoWrap enables or disables automatic error wrapping.
 func ChainWithAutoWrap(auto bool) ChainOption {
 	return func(c *Chain) {
@@ -106,8 +217,8 @@ func ChainWithAutoWrap(auto bool) ChainOption {
 // The function must return an error or nil.
 func (c *Chain) Step(fn func() error) *Chain {
 	if fn == nil {
-		// Panic to enforce valid input
-		panic("Chain.Step: provided function cannot be nil")
+		c.misuse("Chain.Step: provided function cannot be nil")
+		return c
 	}
 	// Create a new step with default configuration
 	step := chainStep{execute: fn, config: stepConfig{}}
@@ -135,21 +246,14 @@ func (c *Chain) Step(fn func() error) *Chain {
 //		})
 func (c *Chain) StepCtx(fn func(ctx context.Context) error) *Chain {
 	if fn == nil {
-		panic("Chain.StepCtx: provided function cannot be nil")
-	}
-	// Wrap fn so it satisfies the internal func() error signature used by
-	// executeStep. The context is captured at execution time via getContextAndCancel.
-	// Close over c.runCtx — set by Run/RunAll to the chain-level context.
-	// This ensures StepCtx steps share the same deadline as the chain,
-	// rather than each getting a fresh full-duration context.
-	wrapped := func() error {
-		ctx := c.runCtx
-		if ctx == nil {
-			ctx = context.Background()
-		}
-		return fn(ctx)
+		c.misuse("Chain.StepCtx: provided function cannot be nil")
+		return c
 	}
-	step := chainStep{execute: wrapped, config: stepConfig{}}
+	// executeStep passes the chain-level context from Run/RunAll, so StepCtx
+	// steps share the chain's deadline rather than each getting a fresh
+	// full-duration context. Storing fn itself (rather than a closure over c)
+	// keeps this true when the step is moved to another chain by Then.
+	step := chainStep{executeCtx: fn, config: stepConfig{}}
 	c.steps = append(c.steps, step)
 	c.lastStep = &c.steps[len(c.steps)-1]
 	return c
@@ -161,8 +265,8 @@ func (c *Chain) Call(fn interface{}, args ...interface{}) *Chain {
 	// Wrap the function and arguments into an executable step
 	wrappedFn, err := c.wrapCallable(fn, args...)
 	if err != nil {
-		// Panic on setup errors to catch them early
-		panic(fmt.Sprintf("Chain.Call setup error: %v", err))
+		c.misuse(fmt.Sprintf("Chain.Call setup error: %v", err))
+		return c
 	}
 	// Add the wrapped function as a step
 	step := chainStep{execute: wrappedFn, config: stepConfig{}}
@@ -171,12 +275,105 @@ func (c *Chain) Call(fn interface{}, args ...interface{}) *Chain {
 	return c
 }
 
+// StepResult adds a step whose function returns a typed result alongside its
+// error. The result of the last successful run is kept and can be read with
+// Result, both after Run and from later steps, so values can flow between
+// steps. A failing run clears the result.
+// Example:
+//
+//	c := errors.NewChain()
+//	errors.StepResult(c, loadUser)
+//	c.Step(func() error {
+//	  user, _ := errors.Result[*User](c, 0)
+//	  return notify(user)
+//	})
+func StepResult[T any](c *Chain, fn func() (T, error)) *Chain {
+	if fn == nil {
+		c.misuse("StepResult: provided function cannot be nil")
+		return c
+	}
+	slot := &stepResult{}
+	step := chainStep{
+		execute: func() error {
+			value, err := fn()
+			slot.mu.Lock()
+			slot.value, slot.ok = value, err == nil
+			slot.mu.Unlock()
+			return err
+		},
+		result: slot,
+	}
+	c.steps = append(c.steps, step)
+	c.lastStep = &c.steps[len(c.steps)-1]
+	return c
+}
+
+// Result returns the result of the step at index, counted from 0 across all
+// steps of c, and true if it was added with StepResult, has run successfully,
+// and its result is a T. Otherwise it returns the zero value and false.
+func Result[T any](c *Chain, index int) (T, bool) {
+	var zero T
+	if c == nil || index < 0 || index >= len(c.steps) || c.steps[index].result == nil {
+		return zero, false
+	}
+	slot := c.steps[index].result
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	if !slot.ok {
+		return zero, false
+	}
+	if slot.value == nil { // A nil interface or pointer result stored as nil
+		return zero, true
+	}
+	value, ok := slot.value.(T)
+	return value, ok
+}
+
+// Then appends the steps of other, with their configuration, to the chain so
+// reusable sub-chains can be composed. The steps run under this chain's context,
+// timeout, logging, and error collection; other is left unchanged and reusable.
+// Retry logging configured on other's steps still uses other's log handler.
+func (c *Chain) Then(other *Chain) *Chain {
+	if other == nil || len(other.steps) == 0 {
+		return c
+	}
+	// Snapshot first so c.Then(c) appends each step exactly once.
+	steps := make([]chainStep, len(other.steps))
+	copy(steps, other.steps)
+	for i := range steps {
+		// Copy mutable config so later With/WithLog calls don't leak between chains.
+		cfg := &steps[i].config
+		if cfg.context != nil {
+			ctx := make(map[string]interface{}, len(cfg.context))
+			for k, v := range cfg.context {
+				ctx[k] = v
+			}
+			cfg.context = ctx
+		}
+		cfg.logAttrs = append([]slog.Attr(nil), cfg.logAttrs...)
+	}
+	c.steps = append(c.steps, steps...)
+	c.lastStep = &c.steps[len(c.steps)-1]
+	return c
+}
+
+// Name labels the last step. The name is appended to log messages, logged as
+// the "step" attribute, and added to the step's error context under "step".
+func (c *Chain) Name(name string) *Chain {
+	if c.lastStep == nil {
+		c.misuse("Chain.Name: must call Step() or Call() before Name()")
+		return c
+	}
+	c.lastStep.config.name = name
+	return c
+}
+
 // Optional marks the last step as optional.
 // Optional steps don't stop the chain on error.
 func (c *Chain) Optional() *Chain {
 	if c.lastStep == nil {
-		// Panic if no step exists to mark as optional
-		panic("Chain.Optional: must call Step() or Call() before Optional()")
+		c.misuse("Chain.Optional: must call Step() or Call() before Optional()")
+		return c
 	}
 	c.lastStep.optional = true
 	return c
@@ -185,8 +382,8 @@ func (c *Chain) Optional() *Chain {
 // WithLog adds logging attributes to the last step.
 func (c *Chain) WithLog(attrs ...slog.Attr) *Chain {
 	if c.lastStep == nil {
-		// Panic if no step exists to configure
-		panic("Chain.WithLog: must call Step() or Call() before WithLog()")
+		c.misuse("Chain.WithLog: must call Step() or Call() before WithLog()")
+		return c
 	}
 	// Append attributes to the step's logging configuration
 	c.lastStep.config.logAttrs = append(c.lastStep.config.logAttrs, attrs...)
@@ -215,8 +412,8 @@ func (c *Chain) MaxErrors(max int) *Chain {
 // With adds a key-value pair to the last step's context.
 func (c *Chain) With(key string, value interface{}) *Chain {
 	if c.lastStep == nil {
-		// Panic if no step exists to configure
-		panic("Chain.With: must call Step() or Call() before With()")
+		c.misuse("Chain.With: must call Step() or Call() before With()")
+		return c
 	}
 	// Initialize context map if nil
 	if c.lastStep.config.context == nil {
@@ -230,8 +427,8 @@ func (c *Chain) With(key string, value interface{}) *Chain {
 // Tag sets an error category for the last step.
 func (c *Chain) Tag(category ErrorCategory) *Chain {
 	if c.lastStep == nil {
-		// Panic if no step exists to configure
-		panic("Chain.Tag: must call Step() or Call() before Tag()")
+		c.misuse("Chain.Tag: must call Step() or Call() before Tag()")
+		return c
 	}
 	c.lastStep.config.category = category
 	return c
@@ -240,18 +437,18 @@ func (c *Chain) Tag(category ErrorCategory) *Chain {
 // Code sets a numeric error code for the last step.
 func (c *Chain) Code(code int) *Chain {
 	if c.lastStep == nil {
-		// Panic if no step exists to configure
-		panic("Chain.Code: must call Step() or Call() before Code()")
+		c.misuse("Chain.Code: must call Step() or Call() before Code()")
+		return c
 	}
 	c.lastStep.config.code = code
 	return c
 }
 
-// Retry configures retry behavior for the last step.
 // Retry configures retry behavior for the last step.
 func (c *Chain) Retry(maxAttempts int, delay time.Duration, opts ...RetryOption) *Chain {
 	if c.lastStep == nil {
-		panic("Chain.Retry: must call Step() or Call() before Retry()")
+		c.misuse("Chain.Retry: must call Step() or Call() before Retry()")
+		return c
 	}
 	if maxAttempts < 1 {
 		maxAttempts = 1
@@ -280,18 +477,30 @@ func (c *Chain) Retry(maxAttempts int, delay time.Duration, opts ...RetryOption)
 		}))
 	}
 
-	// Append any additional retry options
+	// Append chain-wide defaults, then the step's own options so they win
+	retryOpts = append(retryOpts, c.config.retryOpts...)
 	retryOpts = append(retryOpts, opts...)
 	// Create and assign the retry configuration
 	c.lastStep.config.retry = NewRetry(retryOpts...)
 	return c
 }
 
+// Metric sets a metrics label for the last step. Each failure of the step
+// increments the label's count on the sink set by ChainWithMetrics.
+func (c *Chain) Metric(label string) *Chain {
+	if c.lastStep == nil {
+		c.misuse("Chain.Metric: must call Step() or Call() before Metric()")
+		return c
+	}
+	c.lastStep.config.metricsLabel = label
+	return c
+}
+
 // LogOnFail enables automatic logging of errors for the last step.
 func (c *Chain) LogOnFail() *Chain {
 	if c.lastStep == nil {
-		// Panic if no step exists to configure
-		panic("Chain.LogOnFail: must call Step() or Call() before LogOnFail()")
+		c.misuse("Chain.LogOnFail: must call Step() or Call() before LogOnFail()")
+		return c
 	}
 	c.lastStep.config.logOnFail = true
 	return c
@@ -304,9 +513,13 @@ func (c *Chain) Run() error {
 	ctx, cancel := c.getContextAndCancel()
 	defer cancel()
 	c.cancel = cancel
-	c.runCtx = ctx // share deadline with StepCtx closures
 	// Clear any previous errors
 	c.errors = c.errors[:0]
+	c.startProgress()
+	defer c.finishProgress()
+	if c.setupFailed() {
+		return c.errors[0]
+	}
 
 	// Execute each step in sequence
 	for i := range c.steps {
@@ -314,10 +527,10 @@ func (c *Chain) Run() error {
 		// Check if the context has been canceled
 		select {
 		case <-ctx.Done():
-			err := ctx.Err()
+			err := c.deadlineError(ctx, ctx.Err(), i)
 			// Enhance the error with step context
 			enhancedErr := c.enhanceError(err, step)
-			c.errors = append(c.errors, enhancedErr)
+			c.addError(enhancedErr)
 			// Log the context error
 			c.logError(enhancedErr, "Chain stopped due to context error before step", step.config)
 			return enhancedErr
@@ -325,11 +538,14 @@ func (c *Chain) Run() error {
 		}
 
 		// Execute the step
-		err := c.executeStep(ctx, step)
+		c.stepStarted(i, step)
+		err := c.deadlineError(ctx, c.executeStep(ctx, step), i)
+		c.stepFinished()
 		if err != nil {
+			c.recordFailure(step)
 			// Enhance the error with step context
 			enhancedErr := c.enhanceError(err, step)
-			c.errors = append(c.errors, enhancedErr)
+			c.addError(enhancedErr)
 			// Log the error if required
 			if step.config.logOnFail || !step.optional {
 				logMsg := "Chain stopped due to error in step"
@@ -348,43 +564,78 @@ func (c *Chain) Run() error {
 	return nil
 }
 
+// Must runs the chain like Run and panics with the returned error if any
+// non-optional step fails. Intended for startup wiring where failure is fatal;
+// use Run to handle errors.
+func (c *Chain) Must() {
+	if err := c.Run(); err != nil {
+		panic(err)
+	}
+}
+
+// RunWithRetry runs the whole chain like Run, re-running every step from the
+// first under r's attempts, delays, and backoff whenever the run fails; use it
+// for idempotent workflows. Any failure counts as retryable, so r's RetryIf
+// condition is not consulted. Collected errors are cleared before each attempt,
+// so Errors reports the last attempt only. A nil r runs the chain once.
+// Example:
+//
+//	err := chain.RunWithRetry(errors.NewRetry(errors.WithMaxAttempts(3)))
+func (c *Chain) RunWithRetry(r *Retry) error {
+	if r == nil {
+		return c.Run()
+	}
+	return r.Transform(WithRetryIf(func(error) bool { return true })).Execute(c.Run)
+}
+
 // RunAll executes all steps, collecting errors without stopping.
 // It returns a MultiError containing all errors or nil if none occurred.
 func (c *Chain) RunAll() error {
 	ctx, cancel := c.getContextAndCancel()
 	defer cancel()
 	c.cancel = cancel
-	c.runCtx = ctx // share deadline with StepCtx closures
 	c.errors = c.errors[:0]
+	c.startProgress()
+	defer c.finishProgress()
 	multi := NewMultiError()
+	if c.setupFailed() {
+		for _, err := range c.errors {
+			multi.Add(err)
+		}
+		return multi.Single()
+	}
 
 	for i := range c.steps {
 		step := &c.steps[i]
 		select {
 		case <-ctx.Done():
-			err := ctx.Err()
+			err := c.deadlineError(ctx, ctx.Err(), i)
 			enhancedErr := c.enhanceError(err, step)
-			c.errors = append(c.errors, enhancedErr)
+			c.addError(enhancedErr)
 			multi.Add(enhancedErr)
 			c.logError(enhancedErr, "Chain stopped due to context error before step (RunAll)", step.config)
 			goto endRunAll
 		default:
 		}
 
-		err := c.executeStep(ctx, step)
+		c.stepStarted(i, step)
+		err := c.deadlineError(ctx, c.executeStep(ctx, step), i)
+		c.stepFinished()
 		if err != nil {
+			c.recordFailure(step)
 			enhancedErr := c.enhanceError(err, step)
-			c.errors = append(c.errors, enhancedErr)
+			if c.config.dedup && containsMessage(c.errors, enhancedErr) {
+				continue
+			}
+			c.addError(enhancedErr)
 			multi.Add(enhancedErr)
 			if step.config.logOnFail && c.logHandler != nil {
 				c.logError(enhancedErr, "Step failed during RunAll", step.config)
 			}
 			if c.config.maxErrors > 0 && multi.Count() >= c.config.maxErrors {
 				if c.logHandler != nil {
-					// Create a logger to log the max errors condition
-					logger := slog.New(c.logHandler)
-					logger.LogAttrs(
-						context.Background(),
+					// Log the max errors condition
+					c.emit(
 						slog.LevelError,
 						fmt.Sprintf("Stopping RunAll after reaching max errors (%d)", c.config.maxErrors),
 						slog.Int("max_errors", c.config.maxErrors),
@@ -410,6 +661,36 @@ func (c *Chain) Errors() []error {
 	return errs
 }
 
+// MultiError returns the collected errors as a MultiError built with opts
+// (e.g. WithFormatter), or nil if none were collected. The result is a new
+// value each call; Add's duplicate and limit rules apply.
+func (c *Chain) MultiError(opts ...MultiErrorOption) *MultiError {
+	if len(c.errors) == 0 {
+		return nil
+	}
+	multi := NewMultiError(opts...)
+	multi.Add(c.errors...)
+	return multi
+}
+
+// Snapshot returns the progress of the running chain, or of the last run once
+// Run or RunAll has returned. Safe to call from another goroutine during a run,
+// e.g. to report which step a long chain is executing.
+// Example:
+//
+//	go func() {
+//	  for range ticker.C {
+//	    s := chain.Snapshot()
+//	    log.Printf("step %d/%d, %d errors", s.Completed, s.Total, s.Errors)
+//	  }
+//	}()
+//	err := chain.Run()
+func (c *Chain) Snapshot() ChainSnapshot {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	return c.progress
+}
+
 // Len returns the number of steps in the chain.
 func (c *Chain) Len() int {
 	return len(c.steps)
@@ -438,7 +719,11 @@ func (c *Chain) Reset() {
 	// Clear steps and errors
 	c.steps = c.steps[:0]
 	c.errors = c.errors[:0]
+	c.setupErrs = nil
 	c.lastStep = nil
+	c.progressMu.Lock()
+	c.progress = ChainSnapshot{Step: -1}
+	c.progressMu.Unlock()
 }
 
 // Unwrap returns the collected errors (alias for Errors).
@@ -459,6 +744,100 @@ func (c *Chain) getContextAndCancel() (context.Context, context.CancelFunc) {
 	return context.WithCancel(parentCtx)
 }
 
+// misuse panics with msg, or in lenient mode records it as a setup error.
+func (c *Chain) misuse(msg string) {
+	if !c.config.lenient {
+		panic(msg)
+	}
+	c.setupErrs = append(c.setupErrs, New(msg))
+}
+
+// setupFailed copies any recorded setup errors into c.errors and reports
+// whether there were some, in which case no step may run.
+func (c *Chain) setupFailed() bool {
+	c.errors = append(c.errors, c.setupErrs...)
+	return len(c.setupErrs) > 0
+}
+
+// addError appends err to the collected errors and counts it in the progress.
+func (c *Chain) addError(err error) {
+	c.errors = append(c.errors, err)
+	c.progressMu.Lock()
+	c.progress.Errors = len(c.errors)
+	c.progressMu.Unlock()
+}
+
+// startProgress resets the progress for a new run.
+func (c *Chain) startProgress() {
+	c.progressMu.Lock()
+	c.progress = ChainSnapshot{Running: true, Step: -1, Total: len(c.steps)}
+	c.progressMu.Unlock()
+}
+
+// stepStarted records step i as executing.
+func (c *Chain) stepStarted(i int, step *chainStep) {
+	c.progressMu.Lock()
+	c.progress.Step = i
+	c.progress.StepName = step.config.name
+	c.progressMu.Unlock()
+}
+
+// stepFinished records the executing step as completed.
+func (c *Chain) stepFinished() {
+	c.progressMu.Lock()
+	c.progress.Step = -1
+	c.progress.StepName = ""
+	c.progress.Completed++
+	c.progressMu.Unlock()
+}
+
+// finishProgress marks the run as over, including errors recorded without addError.
+func (c *Chain) finishProgress() {
+	c.progressMu.Lock()
+	c.progress.Running = false
+	c.progress.Step = -1
+	c.progress.StepName = ""
+	c.progress.Errors = len(c.errors)
+	c.progressMu.Unlock()
+}
+
+// recordFailure reports a step failure to the metrics sink if the step is labeled.
+func (c *Chain) recordFailure(step *chainStep) {
+	if c.metrics != nil && step.config.metricsLabel != "" {
+		c.metrics.IncFailure(step.config.metricsLabel)
+	}
+}
+
+// deadlineError replaces err with a descriptive timeout error when it stems
+// from the chain's deadline expiring during step i; other errors pass through.
+func (c *Chain) deadlineError(ctx context.Context, err error, i int) error {
+	if err == nil || ctx.Err() != context.DeadlineExceeded || !Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	c.configMu.RLock()
+	timeout := c.config.timeout
+	fn := c.config.timeoutErr
+	c.configMu.RUnlock()
+
+	var e *Error
+	if fn != nil {
+		// Copy, as factories commonly return a shared sentinel.
+		if made := fn(); made != nil {
+			e = made.Copy()
+		}
+	}
+	if e == nil {
+		e = Newf("chain timed out after %s at step %d", timeout, i+1).
+			WithName("ChainTimeout").
+			WithCategory("system").
+			WithCode(504)
+	}
+	if e.cause == nil {
+		e.cause = context.DeadlineExceeded
+	}
+	return e.WithTimeout()
+}
+
 // logError logs an error with step-specific context and attributes.
 // It only logs if a handler is configured and the error is non-nil.
 func (c *Chain) logError(err error, msg string, config stepConfig, additionalAttrs ...slog.Attr) {
@@ -467,14 +846,17 @@ func (c *Chain) logError(err error, msg string, config stepConfig, additionalAtt
 		return
 	}
 
-	// Create a logger on demand using the configured handler
-	logger := slog.New(c.logHandler)
-
 	// Initialize attributes with error and timestamp
-	allAttrs := make([]slog.Attr, 0, 5+len(config.logAttrs)+len(additionalAttrs))
+	allAttrs := make([]slog.Attr, 0, 6+len(config.logAttrs)+len(additionalAttrs))
 	allAttrs = append(allAttrs, slog.Any("error", err))
 	allAttrs = append(allAttrs, slog.Time("timestamp", time.Now()))
 
+	// Identify the step by name if one was set
+	if config.name != "" {
+		msg = msg + ": " + config.name
+		allAttrs = append(allAttrs, slog.String(ctxStep, config.name))
+	}
+
 	// Add step-specific metadata
 	if config.category != "" {
 		allAttrs = append(allAttrs, slog.String("category", string(config.category)))
@@ -488,8 +870,13 @@ func (c *Chain) logError(err error, msg string, config stepConfig, additionalAtt
 	allAttrs = append(allAttrs, config.logAttrs...)
 	allAttrs = append(allAttrs, additionalAttrs...)
 
-	// Add stack trace and error name if the error is of type *Error
+	// Add stack trace and error name if the error is of type *Error,
+	// and honor its log level if one was set
+	level := slog.LevelError
 	if e, ok := err.(*Error); ok {
+		if l, ok := e.LogLevel(); ok {
+			level = l
+		}
 		if stack := e.Stack(); len(stack) > 0 {
 			// Format stack trace, truncating if too long
 			stackStr := "\n\t" + strings.Join(stack, "\n\t")
@@ -503,7 +890,7 @@ func (c *Chain) logError(err error, msg string, config stepConfig, additionalAtt
 		}
 	}
 
-	// Log the error at ERROR level with all attributes
+	// Log the error at its level (ERROR by default) with all attributes
 	// Use a defer to catch any panics during logging
 	defer func() {
 		if r := recover(); r != nil {
@@ -511,7 +898,137 @@ func (c *Chain) logError(err error, msg string, config stepConfig, additionalAtt
 			fmt.Printf("ERROR: Recovered from panic during logging: %v\nAttributes: %v\n", r, allAttrs)
 		}
 	}()
-	logger.LogAttrs(context.Background(), slog.LevelError, msg, allAttrs...)
+	c.emit(level, msg, allAttrs...)
+}
+
+// emit sends a record to the configured handler, through the background
+// queue when asynchronous logging is enabled.
+func (c *Chain) emit(level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.asyncLog != nil {
+		r := slog.NewRecord(time.Now(), level, msg, 0)
+		r.AddAttrs(attrs...)
+		c.asyncLog.enqueue(r)
+		return
+	}
+	slog.New(c.logHandler).LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// Flush blocks until all queued log records have been handled.
+// No-op when asynchronous logging is disabled or the chain has been closed.
+func (c *Chain) Flush() {
+	if c.asyncLog != nil {
+		c.asyncLog.flush()
+	}
+}
+
+// Close drains queued log records and stops the background logging goroutine.
+// Safe to call multiple times; records logged after Close are dropped.
+func (c *Chain) Close() {
+	if c.asyncLog != nil {
+		c.asyncLog.close()
+	}
+}
+
+// DroppedLogs returns the number of log records dropped because the
+// asynchronous buffer was full or the chain was closed.
+func (c *Chain) DroppedLogs() uint64 {
+	if c.asyncLog == nil {
+		return 0
+	}
+	return c.asyncLog.dropped.Load()
+}
+
+// asyncLogger delivers log records to a handler from a single background goroutine.
+type asyncLogger struct {
+	handler   slog.Handler
+	queue     chan logEntry
+	done      chan struct{} // Closed when the worker exits
+	dropped   atomic.Uint64 // Records dropped due to a full buffer or closed logger
+	mu        sync.RWMutex  // Guards closed against concurrent enqueue/close
+	closed    bool
+	closeOnce sync.Once
+}
+
+// logEntry is either a record to handle or a flush marker to acknowledge.
+type logEntry struct {
+	record  slog.Record
+	flushed chan struct{}
+}
+
+// newAsyncLogger starts a worker that drains a queue of bufSize records into handler.
+func newAsyncLogger(handler slog.Handler, bufSize int) *asyncLogger {
+	a := &asyncLogger{
+		handler: handler,
+		queue:   make(chan logEntry, bufSize),
+		done:    make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// run handles queued records in order until the queue is closed.
+func (a *asyncLogger) run() {
+	defer close(a.done)
+	for entry := range a.queue {
+		if entry.flushed != nil {
+			close(entry.flushed)
+			continue
+		}
+		a.handle(entry.record)
+	}
+}
+
+// handle passes r to the handler, recovering from handler panics so one bad
+// record cannot stop the worker.
+func (a *asyncLogger) handle(r slog.Record) {
+	defer func() {
+		if p := recover(); p != nil {
+			fmt.Printf("ERROR: Recovered from panic during logging: %v\n", p)
+		}
+	}()
+	ctx := context.Background()
+	if a.handler.Enabled(ctx, r.Level) {
+		_ = a.handler.Handle(ctx, r)
+	}
+}
+
+// enqueue adds r to the queue without blocking, counting it as dropped if full.
+func (a *asyncLogger) enqueue(r slog.Record) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		a.dropped.Add(1)
+		return
+	}
+	select {
+	case a.queue <- logEntry{record: r}:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// flush waits until every record queued before the call has been handled.
+func (a *asyncLogger) flush() {
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	a.queue <- logEntry{flushed: flushed}
+	a.mu.RUnlock()
+	<-flushed
+}
+
+// close stops accepting records, drains the queue, and waits for the worker.
+func (a *asyncLogger) close() {
+	a.closeOnce.Do(func() {
+		a.mu.Lock()
+		a.closed = true
+		close(a.queue)
+		a.mu.Unlock()
+	})
+	<-a.done
 }
 
 // wrapCallable wraps a function and its arguments into an executable step.
@@ -585,6 +1102,12 @@ func (c *Chain) executeStep(ctx context.Context, step *chainStep) error {
 		// Context is still active, proceed.
 	}
 
+	// Bind context-aware steps to the chain's context.
+	run := step.execute
+	if step.executeCtx != nil {
+		run = func() error { return step.executeCtx(ctx) }
+	}
+
 	// If the step has retry logic configured...
 	if step.config.retry != nil {
 		// Create a new retry instance that is aware of the chain's context.
@@ -592,16 +1115,16 @@ func (c *Chain) executeStep(ctx context.Context, step *chainStep) error {
 		retryExecutor := step.config.retry.Transform(WithContext(ctx))
 
 		// Execute the step's function directly. The retry mechanism will manage the loop,
-		// delays, and context cancellation checks. We pass step.execute without any
+		// delays, and context cancellation checks. We pass the step function without any
 		// extra goroutine wrappers.
-		return retryExecutor.Execute(step.execute)
+		return retryExecutor.Execute(run)
 	}
 
 	// For a simple, non-retrying step, execute the function directly and synchronously
 	// in the current goroutine. This is the simplest, fastest, and most correct approach.
 	// It ensures that database connections are used and returned to the pool sequentially,
 	// preventing the deadlock issue.
-	return step.execute()
+	return run()
 }
 
 // enhanceError wraps an error with additional context from the step.
@@ -623,6 +1146,9 @@ func (c *Chain) enhanceError(err error, step *chainStep) error {
 
 	if step != nil {
 		// Add step-specific context to the error
+		if step.config.name != "" {
+			baseError.With(ctxStep, step.config.name)
+		}
 		if step.config.category != "" && baseError.Category() == "" {
 			baseError.WithCategory(step.config.category)
 		}
@@ -5,6 +5,7 @@ import (
 	stderrs "errors" // Alias for standard errors package to avoid conflicts
 	"fmt"
 	"log/slog" // Structured logging package for testing log output
+	"reflect"
 	"strings"
 	"testing" // Standard Go testing package
 	"time"
@@ -392,6 +393,46 @@ func TestChainErrorEnhancement(t *testing.T) {
 	})
 }
 
+// TestChainRetryJitter tests the chain-wide jitter setting.
+// It verifies disabled jitter yields exact delays and per-step options still win.
+func TestChainRetryJitter(t *testing.T) {
+	// Subtest: Disabled
+	// Verifies two 20ms retry delays take at least the full 40ms.
+	t.Run("Disabled", func(t *testing.T) {
+		attempts := 0
+		c := NewChain(ChainWithJitter(false)).
+			Step(func() error {
+				attempts++
+				return errTest
+			}).
+			Retry(3, 20*time.Millisecond, WithBackoff(ConstantBackoff{}), WithRetryIf(func(error) bool { return true }))
+		if c.lastStep.config.retry.jitter {
+			t.Fatal("Expected jitter to be disabled on the step's retry")
+		}
+
+		start := time.Now()
+		_ = c.Run()
+		elapsed := time.Since(start)
+		if attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts)
+		}
+		if elapsed < 40*time.Millisecond {
+			t.Errorf("Expected at least 40ms without jitter, got %v", elapsed)
+		}
+	})
+
+	// Subtest: StepOverride
+	// Verifies options passed to Retry override the chain default.
+	t.Run("StepOverride", func(t *testing.T) {
+		c := NewChain(ChainWithJitter(false)).
+			Step(func() error { return nil }).
+			Retry(2, time.Millisecond, WithJitter(true))
+		if !c.lastStep.config.retry.jitter {
+			t.Error("Per-step WithJitter(true) should override ChainWithJitter(false)")
+		}
+	})
+}
+
 // TestChainRetryLogic tests retry behavior for different scenarios.
 // It verifies successful retries, failed retries, and context timeout interactions.
 func TestChainRetryLogic(t *testing.T) {
@@ -527,6 +568,190 @@ func TestChainContext(t *testing.T) {
 	})
 }
 
+// TestChainTimeoutError tests the descriptive error returned when a chain times out.
+// It verifies the default error and a custom factory set via ChainWithTimeoutError.
+func TestChainTimeoutError(t *testing.T) {
+	slow := func() error {
+		time.Sleep(30 * time.Millisecond)
+		return nil
+	}
+
+	// Subtest: DefaultTimeoutError
+	// Verifies the default error carries system category, code 504, and step position.
+	t.Run("DefaultTimeoutError", func(t *testing.T) {
+		c := NewChain(ChainWithTimeout(10 * time.Millisecond)).
+			Step(func() error { return nil }).
+			Step(slow).
+			Step(func() error { return nil })
+
+		err := c.Run()
+		e, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("Expected *Error, got %T", err)
+		}
+		if e.Category() != "system" {
+			t.Errorf("Expected category 'system', got %q", e.Category())
+		}
+		if e.Code() != 504 {
+			t.Errorf("Expected code 504, got %d", e.Code())
+		}
+		if !strings.Contains(e.Error(), "chain timed out after 10ms at step 3") {
+			t.Errorf("Expected descriptive timeout message, got %q", e.Error())
+		}
+		if !IsTimeout(e) {
+			t.Error("Expected error to be marked as timeout")
+		}
+		if !stderrs.Is(err, context.DeadlineExceeded) {
+			t.Error("Expected error to wrap context.DeadlineExceeded")
+		}
+	})
+
+	// Subtest: CustomTimeoutError
+	// Verifies a custom factory replaces the default error in RunAll.
+	t.Run("CustomTimeoutError", func(t *testing.T) {
+		c := NewChain(
+			ChainWithTimeout(10*time.Millisecond),
+			ChainWithTimeoutError(func() *Error {
+				return New("checkout took too long").WithCode(408)
+			}),
+		).Step(slow).Step(func() error { return nil })
+
+		err := c.RunAll()
+		e, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("Expected *Error, got %T", err)
+		}
+		if e.Code() != 408 {
+			t.Errorf("Expected code 408, got %d", e.Code())
+		}
+		if !strings.HasPrefix(e.Error(), "checkout took too long") {
+			t.Errorf("Expected custom message, got %q", e.Error())
+		}
+		if !stderrs.Is(err, context.DeadlineExceeded) {
+			t.Error("Expected custom error to wrap context.DeadlineExceeded")
+		}
+	})
+
+	// Subtest: SentinelUnchanged
+	// Verifies a factory returning a shared sentinel doesn't see it mutated.
+	t.Run("SentinelUnchanged", func(t *testing.T) {
+		sentinel := New("checkout timed out").WithCode(408)
+		c := NewChain(
+			ChainWithTimeout(10*time.Millisecond),
+			ChainWithTimeoutError(func() *Error { return sentinel }),
+		).Step(slow).Step(func() error { return nil })
+
+		err := c.Run()
+		if err == error(sentinel) {
+			t.Fatal("Expected a copy of the sentinel, got the sentinel itself")
+		}
+		if !stderrs.Is(err, context.DeadlineExceeded) || !IsTimeout(err) {
+			t.Errorf("Expected a timeout error wrapping context.DeadlineExceeded, got %v", err)
+		}
+		if sentinel.Unwrap() != nil || sentinel.IsTransient() || IsTimeout(sentinel) || len(sentinel.Context()) != 0 {
+			t.Errorf("Sentinel was mutated: cause %v, context %v", sentinel.Unwrap(), sentinel.Context())
+		}
+	})
+}
+
+// gatedLogHandler blocks each Handle call until gate is closed.
+// It's used to simulate a slow log sink that causes the async queue to overflow.
+type gatedLogHandler struct {
+	*memoryLogHandler
+	gate chan struct{}
+}
+
+// Handle waits for the gate before delegating to the memory handler.
+func (h *gatedLogHandler) Handle(ctx context.Context, r slog.Record) error {
+	<-h.gate
+	return h.memoryLogHandler.Handle(ctx, r)
+}
+
+// TestChainAsyncLogging tests asynchronous logging via ChainWithAsyncLogging.
+// It verifies eventual delivery and that overflowing records are counted as dropped.
+func TestChainAsyncLogging(t *testing.T) {
+	// Subtest: Delivered
+	// Verifies queued records reach the handler after Flush.
+	t.Run("Delivered", func(t *testing.T) {
+		logHandler := NewMemoryLogHandler()
+		c := NewChain(ChainWithAsyncLogging(10), ChainWithLogHandler(logHandler))
+		defer c.Close()
+		for i := 0; i < 3; i++ {
+			i := i
+			c.Step(func() error { return fmt.Errorf("async failure %d", i) }).LogOnFail()
+		}
+
+		if err := c.RunAll(); err == nil {
+			t.Fatal("Expected errors from RunAll")
+		}
+		c.Flush()
+
+		if n := strings.Count(logHandler.GetOutput(), "Step failed during RunAll"); n != 3 {
+			t.Errorf("Expected 3 log records, got %d\nOutput: %s", n, logHandler.GetOutput())
+		}
+		if c.DroppedLogs() != 0 {
+			t.Errorf("Expected no dropped records, got %d", c.DroppedLogs())
+		}
+	})
+
+	// Subtest: DropsWhenFull
+	// Verifies records beyond the buffer are dropped and counted while the handler is stalled.
+	t.Run("DropsWhenFull", func(t *testing.T) {
+		logHandler := &gatedLogHandler{memoryLogHandler: NewMemoryLogHandler(), gate: make(chan struct{})}
+		c := NewChain(ChainWithLogHandler(logHandler), ChainWithAsyncLogging(1))
+		for i := 0; i < 10; i++ {
+			i := i
+			c.Step(func() error { return fmt.Errorf("overload failure %d", i) }).LogOnFail()
+		}
+
+		start := time.Now()
+		_ = c.RunAll()
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("RunAll should not block on a stalled handler, took %v", elapsed)
+		}
+		if c.DroppedLogs() == 0 {
+			t.Error("Expected dropped records while the handler is stalled")
+		}
+
+		close(logHandler.gate)
+		c.Close()
+		delivered := strings.Count(logHandler.GetOutput(), "Step failed during RunAll")
+		if uint64(delivered)+c.DroppedLogs() != 10 {
+			t.Errorf("Expected delivered (%d) + dropped (%d) = 10", delivered, c.DroppedLogs())
+		}
+	})
+}
+
+// TestChainMetrics tests per-step failure metrics set with Metric.
+// It verifies labeled failures reach the sink in both Run and RunAll.
+func TestChainMetrics(t *testing.T) {
+	counts := make(map[string]int)
+	sink := MetricsSinkFunc(func(label string) { counts[label]++ })
+
+	c := NewChain(ChainWithMetrics(sink)).
+		Step(func() error { return errStep1 }).Metric("step.fetch").Optional().
+		Step(func() error { return nil }).Metric("step.ok").
+		Step(func() error { return errStep2 }) // Unlabeled: not reported
+
+	_ = c.RunAll()
+	_ = c.Run()
+
+	if counts["step.fetch"] != 2 {
+		t.Errorf("Expected step.fetch count 2, got %d", counts["step.fetch"])
+	}
+	if _, ok := counts["step.ok"]; ok {
+		t.Error("Successful step should not be reported")
+	}
+	if len(counts) != 1 {
+		t.Errorf("Expected only labeled failures, got %v", counts)
+	}
+
+	// Without a sink, labels are accepted and ignored.
+	if err := NewChain().Step(func() error { return errTest }).Metric("x").Run(); err == nil {
+		t.Error("Expected error from failing step")
+	}
+}
+
 // TestChainLogging tests logging behavior for failing steps.
 // It verifies log messages and attributes for optional and non-optional steps.
 func TestChainLogging(t *testing.T) {
@@ -621,6 +846,98 @@ func TestChainLogging(t *testing.T) {
 	})
 }
 
+// TestChainThen tests composing chains with Then.
+// It verifies appended steps run in order with their configuration intact.
+func TestChainThen(t *testing.T) {
+	// Subtest: Order
+	// Verifies all four steps of two composed 2-step chains run in order.
+	t.Run("Order", func(t *testing.T) {
+		var order []string
+		record := func(name string) func() error {
+			return func() error {
+				order = append(order, name)
+				return nil
+			}
+		}
+		authenticate := NewChain().Step(record("auth1")).Step(record("auth2"))
+		authorize := NewChain().Step(record("authz1")).Step(record("authz2"))
+
+		if err := NewChain().Then(authenticate).Then(authorize).Run(); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		want := []string{"auth1", "auth2", "authz1", "authz2"}
+		if !reflect.DeepEqual(order, want) {
+			t.Errorf("Expected order %v, got %v", want, order)
+		}
+		if authenticate.Len() != 2 || authorize.Len() != 2 {
+			t.Error("Then should not modify the appended chain")
+		}
+	})
+
+	// Subtest: ConfigAndContext
+	// Verifies step config travels with the step and StepCtx sees the outer deadline.
+	t.Run("ConfigAndContext", func(t *testing.T) {
+		sub := NewChain().
+			StepCtx(func(ctx context.Context) error {
+				if _, ok := ctx.Deadline(); !ok {
+					return New("missing chain deadline")
+				}
+				return nil
+			}).
+			Step(func() error { return errTest }).Code(418).With("phase", "sub")
+
+		err := NewChain(ChainWithTimeout(time.Second)).Then(sub).With("extra", true).Run()
+		if Code(err) != 418 {
+			t.Fatalf("Expected step code 418, got %d (%v)", Code(err), err)
+		}
+		if Context(err)["phase"] != "sub" {
+			t.Errorf("Expected phase context from sub-chain, got %v", Context(err))
+		}
+		if _, leaked := sub.lastStep.config.context["extra"]; leaked {
+			t.Error("With on the composed chain should not modify the sub-chain")
+		}
+	})
+}
+
+// TestChainStepName tests labeling steps with Name.
+// It verifies the name appears in log output and in the error's "step" context.
+func TestChainStepName(t *testing.T) {
+	logHandler := NewMemoryLogHandler()
+
+	// Subtest: Run
+	// Verifies a named step's failure carries the name in logs and context.
+	t.Run("Run", func(t *testing.T) {
+		logHandler.Reset()
+		err := NewChain(ChainWithLogHandler(logHandler)).
+			Step(func() error { return nil }).Name("load").
+			Step(func() error { return errTest }).Name("fetch-user").
+			Run()
+		if err == nil {
+			t.Fatal("Expected error")
+		}
+
+		logOutput := logHandler.GetOutput()
+		if !strings.Contains(logOutput, "Chain stopped due to error in step: fetch-user") {
+			t.Errorf("Log message missing step name. Got: %s", logOutput)
+		}
+		if !strings.Contains(logOutput, "step=fetch-user") {
+			t.Errorf("Log missing 'step=fetch-user' attribute. Got: %s", logOutput)
+		}
+		if got := Context(err)["step"]; got != "fetch-user" {
+			t.Errorf("Expected step context %q, got %v", "fetch-user", got)
+		}
+	})
+
+	// Subtest: Unnamed
+	// Verifies unnamed steps add no step context.
+	t.Run("Unnamed", func(t *testing.T) {
+		err := NewChain().Step(func() error { return errTest }).Run()
+		if _, ok := Context(err)["step"]; ok {
+			t.Error("Unnamed step should not set step context")
+		}
+	})
+}
+
 // TestChainRunAll tests the RunAll method.
 // It verifies error collection and max error limits.
 func TestChainRunAll(t *testing.T) {
@@ -673,6 +990,39 @@ func TestChainRunAll(t *testing.T) {
 			t.Errorf("Expected exactly 2 errors due to max limit, got %d", len(multiErr.Errors()))
 		}
 	})
+
+	// Subtest: DedupErrors
+	// Verifies that identical failures are collected once with ChainDedupErrors.
+	t.Run("DedupErrors", func(t *testing.T) {
+		shared := func() error { return New("database unavailable") }
+		var step3Executed bool
+		c := NewChain(ChainDedupErrors()).
+			Step(shared).
+			Step(shared).
+			Step(func() error { step3Executed = true; return nil })
+
+		err := c.RunAll()
+
+		if !step3Executed {
+			t.Error("RunAll should continue past deduplicated errors")
+		}
+		if _, ok := err.(*MultiError); ok {
+			t.Fatalf("Expected a single error after dedup, got %v", err)
+		}
+		if err == nil || err.Error() != "database unavailable" {
+			t.Errorf("Expected the shared error, got %v", err)
+		}
+		if len(c.Errors()) != 1 {
+			t.Errorf("Expected 1 collected error, got %d", len(c.Errors()))
+		}
+
+		// Without the option every failure is kept in Errors
+		c = NewChain().Step(shared).Step(shared)
+		_ = c.RunAll()
+		if len(c.Errors()) != 2 {
+			t.Errorf("Expected 2 collected errors without dedup, got %d", len(c.Errors()))
+		}
+	})
 }
 
 // TestChainReset tests the Reset method.
@@ -699,6 +1049,309 @@ func TestChainReset(t *testing.T) {
 	}
 }
 
+// TestChainLenient tests ChainLenient.
+// It verifies that builder misuse is reported by Run and RunAll instead of panicking.
+func TestChainLenient(t *testing.T) {
+	// Subtest: WithLogBeforeStep
+	// Verifies that misuse becomes a setup error and no step runs.
+	t.Run("WithLogBeforeStep", func(t *testing.T) {
+		ran := false
+		c := NewChain(ChainLenient()).
+			WithLog(slog.String("k", "v")).
+			Step(func() error { ran = true; return nil })
+
+		err := c.Run()
+		if err == nil || !strings.Contains(err.Error(), "Chain.WithLog") {
+			t.Fatalf("Expected WithLog setup error, got %v", err)
+		}
+		if ran {
+			t.Error("Steps should not run when setup failed")
+		}
+		if len(c.Errors()) != 1 {
+			t.Errorf("Expected 1 collected error, got %v", c.Errors())
+		}
+	})
+
+	// Subtest: RunAllReportsAll
+	// Verifies that RunAll returns every setup error.
+	t.Run("RunAllReportsAll", func(t *testing.T) {
+		c := NewChain(ChainLenient()).Tag("x").Step(nil).Call("not a function")
+		err := c.RunAll()
+		if err == nil {
+			t.Fatal("Expected setup errors from RunAll")
+		}
+		if len(c.Errors()) != 3 {
+			t.Errorf("Expected 3 setup errors, got %v", c.Errors())
+		}
+		c.Reset()
+		if err := c.Run(); err != nil {
+			t.Errorf("Reset should clear setup errors, got %v", err)
+		}
+	})
+
+	// Subtest: StrictPanics
+	// Verifies that misuse still panics without ChainLenient.
+	t.Run("StrictPanics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected WithLog before Step to panic")
+			}
+		}()
+		NewChain().WithLog(slog.String("k", "v"))
+	})
+}
+
+// TestChainMultiError tests converting collected errors into a MultiError.
+func TestChainMultiError(t *testing.T) {
+	c := NewChain().
+		Step(func() error { return New("first failed") }).
+		Step(func() error { return nil }).
+		Step(func() error { return New("second failed") })
+	if c.MultiError() != nil {
+		t.Error("Expected nil MultiError before running")
+	}
+	_ = c.RunAll()
+
+	multi := c.MultiError()
+	if multi == nil || multi.Count() != 2 {
+		t.Fatalf("Expected MultiError with 2 errors, got %v", multi)
+	}
+	if !stderrs.Is(multi, c.Errors()[0]) {
+		t.Error("Expected MultiError to contain the collected errors")
+	}
+
+	formatted := c.MultiError(WithFormatter(func(errs []error) string {
+		return fmt.Sprintf("%d step errors", len(errs))
+	}))
+	if formatted.Error() != "2 step errors" {
+		t.Errorf("Expected custom formatter output, got %q", formatted.Error())
+	}
+}
+
+// TestChainMust tests that Must panics only when the chain fails.
+func TestChainMust(t *testing.T) {
+	// Subtest: Failing
+	// Verifies that Must panics with the error Run would return.
+	t.Run("Failing", func(t *testing.T) {
+		c := NewChain().Step(func() error { return errTest })
+		defer func() {
+			p := recover()
+			err, ok := p.(error)
+			if !ok || !stderrs.Is(err, errTest) {
+				t.Errorf("Expected panic with the step error, got %v", p)
+			}
+		}()
+		c.Must()
+		t.Error("Must should not return after a failure")
+	})
+
+	// Subtest: Succeeding
+	// Verifies that Must returns normally and runs every step.
+	t.Run("Succeeding", func(t *testing.T) {
+		ran := 0
+		NewChain().
+			Step(func() error { ran++; return nil }).
+			Step(func() error { ran++; return errTest }).Optional().
+			Must()
+		if ran != 2 {
+			t.Errorf("Expected 2 steps to run, got %d", ran)
+		}
+	})
+}
+
+// TestChainSnapshot tests reading chain progress from another goroutine during a run.
+func TestChainSnapshot(t *testing.T) {
+	// Subtest: DuringRun
+	// Verifies the executing step, completed steps, and errors while a step blocks.
+	t.Run("DuringRun", func(t *testing.T) {
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		c := NewChain().
+			Step(func() error { return nil }).
+			Step(func() error { return New("optional failure") }).Optional().
+			Step(func() error { close(entered); <-release; return nil }).Name("wait")
+
+		if s := c.Snapshot(); s.Running || s.Step != -1 {
+			t.Errorf("Expected idle snapshot before Run, got %+v", s)
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- c.Run() }()
+		<-entered
+
+		want := ChainSnapshot{Running: true, Step: 2, StepName: "wait", Completed: 2, Errors: 1, Total: 3}
+		if s := c.Snapshot(); s != want {
+			t.Errorf("Expected %+v during run, got %+v", want, s)
+		}
+
+		close(release)
+		if err := <-done; err != nil {
+			t.Fatalf("Expected Run to succeed, got %v", err)
+		}
+		want = ChainSnapshot{Step: -1, Completed: 3, Errors: 1, Total: 3}
+		if s := c.Snapshot(); s != want {
+			t.Errorf("Expected %+v after run, got %+v", want, s)
+		}
+	})
+
+	// Subtest: Polling
+	// Verifies that concurrent polling observes monotonic progress.
+	t.Run("Polling", func(t *testing.T) {
+		c := NewChain()
+		for i := 0; i < 20; i++ {
+			c.Step(func() error { time.Sleep(time.Millisecond); return nil })
+		}
+		stop := make(chan struct{})
+		polled := make(chan int, 1)
+		go func() {
+			last := 0
+			for {
+				select {
+				case <-stop:
+					polled <- last
+					return
+				default:
+				}
+				s := c.Snapshot()
+				if s.Completed < last {
+					t.Errorf("Completed went backwards: %d after %d", s.Completed, last)
+				}
+				last = s.Completed
+			}
+		}()
+		_ = c.RunAll()
+		close(stop)
+		<-polled
+		if s := c.Snapshot(); s.Completed != 20 || s.Running {
+			t.Errorf("Expected 20 completed steps after RunAll, got %+v", s)
+		}
+	})
+}
+
+// TestChainRunWithRetry tests retrying the whole chain until it succeeds.
+func TestChainRunWithRetry(t *testing.T) {
+	// Subtest: SucceedsOnThirdAttempt
+	// Verifies that every step re-runs and the final attempt's result is returned.
+	t.Run("SucceedsOnThirdAttempt", func(t *testing.T) {
+		first, second := 0, 0
+		c := NewChain().
+			Step(func() error { first++; return nil }).
+			Step(func() error {
+				second++
+				if second < 3 {
+					return fmt.Errorf("attempt %d failed", second)
+				}
+				return nil
+			})
+
+		r := NewRetry(WithMaxAttempts(3), WithDelay(time.Millisecond), WithJitter(false))
+		if err := c.RunWithRetry(r); err != nil {
+			t.Fatalf("Expected success after retries, got %v", err)
+		}
+		if first != 3 || second != 3 {
+			t.Errorf("Expected both steps to run 3 times, got %d and %d", first, second)
+		}
+		if c.HasErrors() {
+			t.Errorf("Expected no errors from the successful attempt, got %v", c.Errors())
+		}
+	})
+
+	// Subtest: GivesUp
+	// Verifies that the last failure is returned once attempts run out.
+	t.Run("GivesUp", func(t *testing.T) {
+		calls := 0
+		c := NewChain().Step(func() error { calls++; return fmt.Errorf("always fails") })
+		err := c.RunWithRetry(NewRetry(WithMaxAttempts(2), WithDelay(time.Millisecond)))
+		if err == nil || !strings.Contains(err.Error(), "always fails") {
+			t.Errorf("Expected the step's error, got %v", err)
+		}
+		if calls != 2 || len(c.Errors()) != 1 {
+			t.Errorf("Expected 2 calls and 1 error from the last attempt, got %d and %v", calls, c.Errors())
+		}
+	})
+}
+
+// TestChainLogLevel verifies that chain logging honors an error's log level.
+func TestChainLogLevel(t *testing.T) {
+	logHandler := NewMemoryLogHandler()
+
+	// Subtest: WarnLevel
+	// Verifies that an error marked as a warning is logged at LevelWarn.
+	t.Run("WarnLevel", func(t *testing.T) {
+		logHandler.Reset()
+		c := NewChain(ChainWithLogHandler(logHandler)).
+			Step(func() error { return New("cache miss").WithLogLevel(slog.LevelWarn) }).LogOnFail()
+		if err := c.Run(); err == nil {
+			t.Fatal("Expected error")
+		}
+		if out := logHandler.GetOutput(); !strings.Contains(out, "level=WARN") || strings.Contains(out, "level=ERROR") {
+			t.Errorf("Expected a WARN record only, got: %s", out)
+		}
+	})
+
+	// Subtest: DefaultLevel
+	// Verifies that errors without a log level are still logged at LevelError.
+	t.Run("DefaultLevel", func(t *testing.T) {
+		logHandler.Reset()
+		c := NewChain(ChainWithLogHandler(logHandler)).
+			Step(func() error { return New("boom") }).LogOnFail()
+		_ = c.Run()
+		if out := logHandler.GetOutput(); !strings.Contains(out, "level=ERROR") {
+			t.Errorf("Expected an ERROR record, got: %s", out)
+		}
+	})
+}
+
+// TestChainStepResult tests typed step results and their propagation.
+func TestChainStepResult(t *testing.T) {
+	// Subtest: Propagation
+	// Verifies that a result is readable by later steps and after Run.
+	t.Run("Propagation", func(t *testing.T) {
+		c := NewChain()
+		StepResult(c, func() (int, error) { return 21, nil })
+		StepResult(c, func() (string, error) {
+			n, ok := Result[int](c, 0)
+			if !ok {
+				return "", New("missing input")
+			}
+			return fmt.Sprint(n * 2), nil
+		})
+		if err := c.Run(); err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if got, ok := Result[string](c, 1); !ok || got != "42" {
+			t.Errorf("Result[string](c, 1) = %q, %v, want 42, true", got, ok)
+		}
+		if _, ok := Result[string](c, 0); ok {
+			t.Error("Result() with the wrong type should report false")
+		}
+		if _, ok := Result[int](c, 5); ok {
+			t.Error("Result() out of range should report false")
+		}
+	})
+
+	// Subtest: ShortCircuit
+	// Verifies that a failing step stops the chain and leaves no result.
+	t.Run("ShortCircuit", func(t *testing.T) {
+		c := NewChain()
+		ran := false
+		StepResult(c, func() (int, error) { return 1, errTest })
+		c.Step(func() error { ran = true; return nil })
+		if err := c.Run(); err == nil {
+			t.Fatal("Run() should fail")
+		}
+		if ran {
+			t.Error("Steps after a failing StepResult should not run")
+		}
+		if _, ok := Result[int](c, 0); ok {
+			t.Error("Result() of a failed step should report false")
+		}
+		if _, ok := Result[int](c, 1); ok {
+			t.Error("Result() of a plain Step should report false")
+		}
+	})
+}
+
 // TestChainReflectionCall tests the Call method with reflection.
 // It verifies that functions with arguments are handled correctly.
 func TestChainReflectionCall(t *testing.T) {
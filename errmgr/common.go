@@ -148,3 +148,42 @@ var (
 	UserError       = Categorized(CategoryUser, "UserError", "user error: %s")
 	ValidationError = Categorized(CategoryValidation, "ValidationError", "validation error: %s")
 )
+
+// IsConflict reports whether err or any error in its chain has CodeConflict.
+func IsConflict(err error) bool {
+	return hasCode(err, CodeConflict)
+}
+
+// IsForbidden reports whether err or any error in its chain has CodeForbidden.
+func IsForbidden(err error) bool {
+	return hasCode(err, CodeForbidden)
+}
+
+// IsNotFound reports whether err or any error in its chain has CodeNotFound.
+// Example: if errmgr.IsNotFound(err) { w.WriteHeader(http.StatusNotFound) }.
+func IsNotFound(err error) bool {
+	return hasCode(err, CodeNotFound)
+}
+
+// IsRateLimited reports whether err or any error in its chain has CodeTooManyRequests.
+func IsRateLimited(err error) bool {
+	return hasCode(err, CodeTooManyRequests)
+}
+
+// IsServiceUnavailable reports whether err or any error in its chain has CodeServiceUnavailable.
+func IsServiceUnavailable(err error) bool {
+	return hasCode(err, CodeServiceUnavailable)
+}
+
+// IsUnauthorized reports whether err or any error in its chain has CodeUnauthorized.
+func IsUnauthorized(err error) bool {
+	return hasCode(err, CodeUnauthorized)
+}
+
+// hasCode walks the chain of err looking for an *errors.Error with the given code.
+func hasCode(err error, code int) bool {
+	return errors.Find(err, func(e error) bool {
+		ce, ok := e.(*errors.Error)
+		return ok && ce.Code() == code
+	}) != nil
+}
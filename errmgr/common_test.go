@@ -1,6 +1,7 @@
 package errmgr
 
 import (
+	"fmt"
 	"github.com/olekukonko/errors"
 	"testing"
 )
@@ -121,3 +122,51 @@ func TestCategorizedTemplates(t *testing.T) {
 		})
 	}
 }
+
+func TestCodePredicates(t *testing.T) {
+	predicates := map[string]func(error) bool{
+		"IsConflict":           IsConflict,
+		"IsForbidden":          IsForbidden,
+		"IsNotFound":           IsNotFound,
+		"IsRateLimited":        IsRateLimited,
+		"IsServiceUnavailable": IsServiceUnavailable,
+		"IsUnauthorized":       IsUnauthorized,
+	}
+	tests := []struct {
+		name string
+		code int
+	}{
+		{"IsConflict", CodeConflict},
+		{"IsForbidden", CodeForbidden},
+		{"IsNotFound", CodeNotFound},
+		{"IsRateLimited", CodeTooManyRequests},
+		{"IsServiceUnavailable", CodeServiceUnavailable},
+		{"IsUnauthorized", CodeUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := errors.New("direct").WithCode(tt.code)
+			defer err.Free()
+			wrapped := errors.New("handler failed").Wrap(err)
+			defer wrapped.Free()
+
+			for name, pred := range predicates {
+				want := name == tt.name
+				if got := pred(err); got != want {
+					t.Errorf("%s(code %d) = %v, want %v", name, tt.code, got, want)
+				}
+				if got := pred(wrapped); got != want {
+					t.Errorf("%s(wrapped code %d) = %v, want %v", name, tt.code, got, want)
+				}
+			}
+		})
+	}
+
+	if !IsNotFound(ErrNotFound) || !IsUnauthorized(ErrAuthFailed("user", "bad password")) {
+		t.Error("Predicates should match the predefined errors")
+	}
+	if IsNotFound(nil) || IsNotFound(fmt.Errorf("plain")) {
+		t.Error("Predicates should be false for nil and plain errors")
+	}
+}
@@ -3,21 +3,27 @@
 package errmgr
 
 import (
+	"context"
 	"fmt"
 	"github.com/olekukonko/errors"
+	"github.com/olekukonko/errors/internal/errstate"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // Config holds configuration for the errmgr package.
 type Config struct {
-	DisableMetrics bool // Disables counting and tracking if true
+	DisableMetrics  bool // Disables counting and tracking if true
+	TrackLastErrors bool // Keeps a copy of each name's latest error for GetLastError and LastErrors
 }
 
 // cachedConfig holds the current configuration, updated only on Configure().
 type cachedConfig struct {
 	disableErrMgr bool
+	trackLast     bool
 }
 
 var (
@@ -25,6 +31,9 @@ var (
 	configMu      sync.RWMutex
 	registry      = errorRegistry{counts: shardedCounter{}}
 	codes         = codeRegistry{m: make(map[string]int)}
+	contextKeys   contextKeyRegistry
+	sink          atomic.Pointer[func(*errors.Error)] // Optional forwarder set by SetSink
+	now           = time.Now                          // Clock for rate thresholds; replaced in tests
 )
 
 func init() {
@@ -37,7 +46,11 @@ type errorRegistry struct {
 	funcs      sync.Map       // map[string]func(...interface{}) *errors.Error: Custom error functions
 	counts     shardedCounter // Sharded counter for error occurrences
 	thresholds sync.Map       // map[string]uint64: Alert thresholds
+	rates      sync.Map       // map[string]*rateThreshold: Per-window alert thresholds
+	severities sync.Map       // map[string]errors.Severity: Minimum severity that alerts immediately
+	intervals  sync.Map       // map[string]*minInterval: Minimum time between counted occurrences
 	alerts     sync.Map       // map[string]*alertChannel: Alert channels
+	last       sync.Map       // map[string]*errors.Error: Copy of the latest error, if tracked
 	mu         sync.RWMutex   // Protects alerts map
 }
 
@@ -47,6 +60,73 @@ type codeRegistry struct {
 	mu sync.RWMutex
 }
 
+// contextKeyRegistry lists the context.Context keys DefineCtx copies into errors.
+type contextKeyRegistry struct {
+	fields []contextKeyField
+	mu     sync.RWMutex
+}
+
+// contextKeyField maps a context.Context value key to an error context field.
+type contextKeyField struct {
+	key   interface{}
+	field string
+}
+
+// MetricCount is a single error name and its occurrence count, as returned by MetricsSorted.
+type MetricCount struct {
+	Name  string
+	Count uint64
+}
+
+// rateThreshold counts occurrences in fixed windows for SetRateThreshold.
+type rateThreshold struct {
+	limit  uint64
+	window time.Duration
+	start  time.Time // Start of the current window
+	count  uint64    // Occurrences in the current window
+	fired  bool      // Whether the current window has already alerted
+	mu     sync.Mutex
+}
+
+// observe records one occurrence at t and reports whether it pushed the
+// window's count over the limit for the first time.
+func (r *rateThreshold) observe(t time.Time) (uint64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t.Sub(r.start) >= r.window {
+		r.start, r.count, r.fired = t, 0, false
+	}
+	r.count++
+	if r.count > r.limit && !r.fired {
+		r.fired = true
+		return r.count, true
+	}
+	return r.count, false
+}
+
+// minInterval coalesces bursts for SetMinInterval, counting at most one
+// occurrence per interval.
+type minInterval struct {
+	interval time.Duration
+	last     time.Time // Time of the last counted occurrence
+	counted  bool      // Whether any occurrence has been counted yet
+	dropped  uint64    // Occurrences coalesced into earlier ones
+	mu       sync.Mutex
+}
+
+// allow reports whether an occurrence at t should be counted, recording it
+// as dropped otherwise.
+func (m *minInterval) allow(t time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counted && t.Sub(m.last) < m.interval {
+		m.dropped++
+		return false
+	}
+	m.last, m.counted = t, true
+	return true
+}
+
 // shardedCounter provides a low-contention counter for error occurrences.
 type shardedCounter struct {
 	counts sync.Map
@@ -55,10 +135,9 @@ type shardedCounter struct {
 // Categorized creates a categorized error template and returns a function to create errors.
 // The returned function applies the category to each error instance.
 func Categorized(category errors.ErrorCategory, name, template string) func(...interface{}) *errors.Error {
-	f := Define(name, template)
-	return func(args ...interface{}) *errors.Error {
-		return f(args...).WithCategory(category)
-	}
+	return define(name, template, func(err *errors.Error) {
+		err.WithCategory(category)
+	})
 }
 
 // CloseMonitor closes the alert channel for a specific error name.
@@ -68,13 +147,7 @@ func CloseMonitor(name string) {
 	defer registry.mu.Unlock()
 
 	if ch, ok := registry.alerts.Load(name); ok {
-		ac := ch.(*alertChannel)
-		ac.mu.Lock()
-		if !ac.closed {
-			close(ac.ch)
-			ac.closed = true
-		}
-		ac.mu.Unlock()
+		ch.(*alertChannel).close()
 		registry.alerts.Delete(name)
 	}
 }
@@ -85,18 +158,16 @@ func Coded(name, template string, code int) func(...interface{}) *errors.Error {
 	codes.mu.Lock()
 	codes.m[name] = code
 	codes.mu.Unlock()
-	base := Define(name, template)
-	return func(args ...interface{}) *errors.Error {
-		err := base(args...)
-		return err.WithCode(code)
-	}
+	return define(name, template, func(err *errors.Error) {
+		err.WithCode(code)
+	})
 }
 
 // Configure updates the global configuration for the errmgr package.
 // Thread-safe; applies immediately to all subsequent operations.
 func Configure(cfg Config) {
 	configMu.Lock()
-	currentConfig = cachedConfig{disableErrMgr: cfg.DisableMetrics}
+	currentConfig = cachedConfig{disableErrMgr: cfg.DisableMetrics, trackLast: cfg.TrackLastErrors}
 	configMu.Unlock()
 }
 
@@ -109,20 +180,179 @@ func Copy(err *errors.Error) *errors.Error {
 // Define creates a templated error that formats a message with provided arguments.
 // The error is tracked in the registry if error management is enabled.
 func Define(name, template string) func(...interface{}) *errors.Error {
+	return define(name, template, nil)
+}
+
+// DefineCtx is like Define, but the returned function takes a context.Context and
+// copies the values of keys registered with SetContextKey into each error, so
+// request-scoped data such as a request ID is attached without a With at every site.
+// Example:
+//
+//	errmgr.SetContextKey(requestIDKey{}, "request_id")
+//	ErrLookup := errmgr.DefineCtx("ErrLookup", "lookup of %s failed")
+//	err := ErrLookup(r.Context(), "user42") // carries request_id
+func DefineCtx(name, template string) func(context.Context, ...interface{}) *errors.Error {
+	register(name, template)
+	return func(ctx context.Context, args ...interface{}) *errors.Error {
+		return build(name, template, args, func(err *errors.Error) {
+			if ctx == nil {
+				return
+			}
+			contextKeys.mu.RLock()
+			defer contextKeys.mu.RUnlock()
+			for _, kf := range contextKeys.fields {
+				if v := ctx.Value(kf.key); v != nil {
+					err.With(kf.field, v)
+				}
+			}
+		})
+	}
+}
+
+// DefineStrict is like Define, but the returned function checks the number of
+// arguments against the template's verbs. On a mismatch it returns, instead of a
+// message garbled with %!s(MISSING) or %!(EXTRA ...), an error named name whose
+// message describes the mismatch and whose context has "template_mismatch" set
+// to true. Templates using explicit argument indexes such as %[1]s are not checked.
+func DefineStrict(name, template string) func(...interface{}) *errors.Error {
+	register(name, template)
+	want := templateArgs(template)
+	return func(args ...interface{}) *errors.Error {
+		if want < 0 || len(args) == want {
+			return build(name, template, args, nil)
+		}
+		return build(name, template, args, func(err *errors.Error) {
+			err.Msgf("%s: template %q expects %d argument(s), got %d", name, template, want, len(args)).
+				With("template_mismatch", true)
+		})
+	}
+}
+
+// templateArgs returns the number of arguments a printf template consumes,
+// counting * widths and precisions, or -1 if it uses explicit argument indexes.
+func templateArgs(template string) int {
+	n := 0
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' {
+			continue
+		}
+		for i++; i < len(template); i++ {
+			c := template[i]
+			if c == '[' {
+				return -1
+			}
+			if c == '*' {
+				n++
+				continue
+			}
+			if strings.IndexByte("+-# 0123456789.", c) < 0 {
+				if c != '%' {
+					n++
+				}
+				break
+			}
+		}
+	}
+	return n
+}
+
+// define implements Define, applying decorate (if non-nil) to each error.
+func define(name, template string, decorate func(*errors.Error)) func(...interface{}) *errors.Error {
+	register(name, template)
+	return func(args ...interface{}) *errors.Error {
+		return build(name, template, args, decorate)
+	}
+}
+
+// register records a template and its counter name for Define-style constructors.
+func register(name, template string) {
 	registry.templates.Store(name, template)
 	if !currentConfig.disableErrMgr {
 		registry.counts.RegisterName(name)
 	}
-	return func(args ...interface{}) *errors.Error {
-		var buf strings.Builder
-		buf.Grow(len(template) + len(name) + len(args)*10)
-		fmt.Fprintf(&buf, template, args...)
-		err := errors.New(buf.String()).WithName(name).WithTemplate(template)
-		if !currentConfig.disableErrMgr {
-			registry.counts.Inc(name)
+}
+
+// build formats a templated error and applies decorate (if non-nil) before the
+// error is counted and published, so subscribers see the fully built error.
+func build(name, template string, args []interface{}, decorate func(*errors.Error)) *errors.Error {
+	var buf strings.Builder
+	buf.Grow(len(template) + len(name) + len(args)*10)
+	fmt.Fprintf(&buf, template, args...)
+	err := errors.New(buf.String()).WithName(name).WithTemplate(template)
+	if decorate != nil {
+		decorate(err)
+	}
+	if !currentConfig.disableErrMgr {
+		emit(name, err, record(name))
+	}
+	return err
+}
+
+// record counts one occurrence of name unless SetMinInterval coalesces it into
+// a recent one, and reports whether it was counted.
+func record(name string) bool {
+	if mi, ok := registry.intervals.Load(name); ok && !mi.(*minInterval).allow(now()) {
+		return false
+	}
+	registry.counts.Inc(name)
+	return true
+}
+
+// emit forwards a newly created error to subscribers and the sink, if set,
+// and alerts the name's monitor if the error meets its severity alert.
+// The last error is only tracked for counted occurrences.
+func emit(name string, err *errors.Error, counted bool) {
+	if minSev, ok := registry.severities.Load(name); ok && err.Severity() >= minSev.(errors.Severity) {
+		if _, ok := registry.alerts.Load(name); ok {
+			alert := errors.New(fmt.Sprintf("%s reached severity %s", name, err.Severity())).
+				WithName(name).
+				WithSeverity(err.Severity()).
+				Wrap(err.Copy())
+			sendAlert(name, alert, registry.counts.Value(name))
 		}
-		return err
 	}
+	if counted && currentConfig.trackLast {
+		// Store a copy, as the caller may Free err; replaced copies are left
+		// to the GC since a concurrent reader may still be copying them.
+		registry.last.Store(name, err.Copy())
+	}
+	subscriptions.publish(name, err)
+	if fn := sink.Load(); fn != nil {
+		(*fn)(err)
+	}
+}
+
+// GetLastError returns a copy of the most recent error created for name, or nil
+// if there is none or Config.TrackLastErrors is off. The caller owns the copy.
+func GetLastError(name string) *errors.Error {
+	if last, ok := registry.last.Load(name); ok {
+		return last.(*errors.Error).Copy()
+	}
+	return nil
+}
+
+// LastErrors returns copies of the most recent error of every tracked name,
+// keyed by name, e.g. for a "recent errors" diagnostics page. Empty unless
+// Config.TrackLastErrors is on. The caller owns the copies.
+func LastErrors() map[string]*errors.Error {
+	result := make(map[string]*errors.Error)
+	registry.last.Range(func(key, value interface{}) bool {
+		result[key.(string)] = value.(*errors.Error).Copy()
+		return true
+	})
+	return result
+}
+
+// Coalesced returns how many occurrences of name SetMinInterval has left
+// uncounted since the interval was set or the registry was Reset.
+func Coalesced(name string) uint64 {
+	if mi, ok := registry.intervals.Load(name); ok {
+		m := mi.(*minInterval)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return m.dropped
+	}
+	return 0
 }
 
 // GetThreshold returns the current threshold for an error name, if set.
@@ -144,28 +374,25 @@ func (c *shardedCounter) Inc(name string) uint64 {
 	if thresh, ok := registry.thresholds.Load(name); ok {
 		total := atomic.LoadUint64(count)
 		if total >= thresh.(uint64) {
-			if ch, ok := registry.alerts.Load(name); ok {
-				ac := ch.(*alertChannel)
-				ac.mu.Lock()
-				if !ac.closed {
-					alert := errors.New(fmt.Sprintf("%s count exceeded threshold: %d", name, total)).
-						WithName(name)
-					for i := uint64(0); i < total; i++ {
-						_ = alert.Increment()
-					}
-					select {
-					case ac.ch <- alert:
-					default: // Drop if channel is full
-					}
-				}
-				ac.mu.Unlock()
+			if _, ok := registry.alerts.Load(name); ok {
+				alert := errors.New(fmt.Sprintf("%s count exceeded threshold: %d", name, total)).
+					WithName(name)
+				sendAlert(name, alert, total)
 			}
 		}
 	}
+	if rate, ok := registry.rates.Load(name); ok {
+		r := rate.(*rateThreshold)
+		if count, exceeded := r.observe(now()); exceeded {
+			alert := errors.New(fmt.Sprintf("%s rate exceeded threshold: %d in %s", name, count, r.window)).
+				WithName(name)
+			sendAlert(name, alert, count)
+		}
+	}
 	return newCount
 }
 
-// ListNames returns all registered error names in the counter.
+// ListNames returns all registered error names in the counter, sorted alphabetically.
 // Thread-safe; returns an empty slice if no names are registered.
 func (c *shardedCounter) ListNames() []string {
 	var names []string
@@ -173,6 +400,7 @@ func (c *shardedCounter) ListNames() []string {
 		names = append(names, key.(string))
 		return true
 	})
+	sort.Strings(names)
 	return names
 }
 
@@ -197,19 +425,46 @@ func Metrics() map[string]uint64 {
 	return counts
 }
 
+// MetricsSorted returns the same counts as Metrics as a slice sorted by name,
+// for stable logs, dumps and snapshots.
+// Returns nil if error management is disabled or no counts exist.
+func MetricsSorted() []MetricCount {
+	counts := Metrics()
+	if counts == nil {
+		return nil
+	}
+	sorted := make([]MetricCount, 0, len(counts))
+	for name, count := range counts {
+		sorted = append(sorted, MetricCount{Name: name, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
+
+// Names returns every error name registered through Define, Coded, Categorized
+// or Tracked, sorted alphabetically. Returns nil if error management is disabled.
+func Names() []string {
+	if currentConfig.disableErrMgr {
+		return nil
+	}
+	return registry.counts.ListNames()
+}
+
 // RegisterName ensures a counter exists for the name without incrementing it.
 // Thread-safe; useful for pre-registering error names.
 func (c *shardedCounter) RegisterName(name string) {
 	c.counts.LoadOrStore(name, new(uint64))
 }
 
-// RemoveThreshold removes the threshold for a specific error name.
+// RemoveThreshold removes the count and rate thresholds for a specific error name.
 // Thread-safe; no effect if no threshold exists.
 func RemoveThreshold(name string) {
 	registry.thresholds.Delete(name)
+	registry.rates.Delete(name)
 }
 
-// Reset clears all counters and removes their registrations.
+// Reset clears all counters, their registrations, tracked last errors, and
+// coalesced counts; minimum intervals stay set.
 // Has no effect if error management is disabled.
 func Reset() {
 	if currentConfig.disableErrMgr {
@@ -220,6 +475,17 @@ func Reset() {
 		registry.counts.counts.Delete(key)
 		return true
 	})
+	registry.last.Range(func(key, _ interface{}) bool {
+		registry.last.Delete(key)
+		return true
+	})
+	registry.intervals.Range(func(_, value interface{}) bool {
+		m := value.(*minInterval)
+		m.mu.Lock()
+		m.counted, m.dropped = false, 0
+		m.mu.Unlock()
+		return true
+	})
 }
 
 // ResetCounter resets the occurrence counter for a specific error type.
@@ -238,6 +504,71 @@ func (c *shardedCounter) Reset(name string) {
 	}
 }
 
+// SetContextKey registers a context.Context value key whose value DefineCtx copies
+// into each error under field. Registering the same key again replaces its field.
+func SetContextKey(key interface{}, field string) {
+	contextKeys.mu.Lock()
+	defer contextKeys.mu.Unlock()
+	for i, kf := range contextKeys.fields {
+		if kf.key == key {
+			contextKeys.fields[i].field = field
+			return
+		}
+	}
+	contextKeys.fields = append(contextKeys.fields, contextKeyField{key: key, field: field})
+}
+
+// SetSink registers fn to receive every error created through Define, Coded,
+// Categorized and Tracked, e.g. to forward them to Sentry or a log aggregator.
+// fn runs synchronously on the creating goroutine, so it must be cheap and hand
+// off slow work itself; it receives the caller's error, so Copy it before keeping
+// it past the call. Pass nil to remove the sink. Not called when metrics are disabled.
+func SetSink(fn func(*errors.Error)) {
+	if fn == nil {
+		sink.Store(nil)
+		return
+	}
+	sink.Store(&fn)
+}
+
+// SetMinInterval makes the registry count occurrences of name, and update its
+// last error, at most once per interval, so a tight loop creating the same
+// error cannot inflate counts or flood thresholds. Errors are still created,
+// published and sent to the sink; the skipped occurrences are reported by
+// Coalesced. A zero or negative interval removes the limit.
+func SetMinInterval(name string, interval time.Duration) {
+	if interval <= 0 {
+		registry.intervals.Delete(name)
+		return
+	}
+	registry.intervals.Store(name, &minInterval{interval: interval})
+}
+
+// SetRateThreshold alerts the name's Monitor when more than count errors are
+// created within window. Time is split into consecutive windows starting at the
+// first occurrence after the previous one ended; each window alerts at most once,
+// so the alert re-arms once the rate drops. It is independent of SetThreshold.
+// A zero count or window removes the rate threshold.
+func SetRateThreshold(name string, count uint64, window time.Duration) {
+	if count == 0 || window <= 0 {
+		registry.rates.Delete(name)
+		return
+	}
+	registry.rates.Store(name, &rateThreshold{limit: count, window: window})
+}
+
+// SetSeverityAlert makes every error created for name with a severity of at
+// least min send an alert to the name's Monitor immediately, regardless of
+// count thresholds. The alert wraps a copy of the error. Pass errors.SeverityNone
+// to remove the alert.
+func SetSeverityAlert(name string, min errors.Severity) {
+	if min == errors.SeverityNone {
+		registry.severities.Delete(name)
+		return
+	}
+	registry.severities.Store(name, min)
+}
+
 // SetThreshold sets a count threshold for an error name, triggering alerts when exceeded.
 // Alerts are sent to the Monitor channel if one exists for the name.
 func SetThreshold(name string, threshold uint64) {
@@ -252,10 +583,43 @@ func Tracked(name string, fn func(...interface{}) *errors.Error) func(...interfa
 		registry.counts.RegisterName(name)
 	}
 	return func(args ...interface{}) *errors.Error {
+		counted := false
 		if !currentConfig.disableErrMgr {
-			registry.counts.Inc(name)
+			counted = record(name)
+		}
+		err := fn(args...)
+		if !currentConfig.disableErrMgr && err != nil {
+			emit(name, err, counted)
+		}
+		return err
+	}
+}
+
+// sendAlert delivers alert, with its Count set to count, to the Monitor
+// registered for name, if any, on both its Alerts and Events channels; Events
+// receives its own copy. Never blocks; the alert is dropped if a channel is full or closed.
+func sendAlert(name string, alert *errors.Error, count uint64) {
+	ch, ok := registry.alerts.Load(name)
+	if !ok {
+		return
+	}
+	ac := ch.(*alertChannel)
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	if ac.closed {
+		return
+	}
+	errstate.SetCount(alert, count)
+	select {
+	case ac.ch <- alert:
+	default: // Drop if channel is full
+	}
+	if ac.events != nil {
+		select {
+		// A separate copy, so a consumer freeing or changing one doesn't affect the other.
+		case ac.events <- Alert{Name: name, Count: count, Err: alert.Copy(), Time: now()}:
+		default:
 		}
-		return fn(args...)
 	}
 }
 
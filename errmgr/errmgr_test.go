@@ -1,9 +1,13 @@
 package errmgr
 
 import (
+	"context"
 	"fmt"
 	"github.com/olekukonko/errors"
+	"reflect"
+	"sort"
 	"testing"
+	"time"
 )
 
 func TestMain(m *testing.M) {
@@ -94,6 +98,27 @@ func TestMetrics(t *testing.T) {
 	}
 }
 
+func TestMetricsSorted(t *testing.T) {
+	Reset()
+	zeta := Define("sorted_zeta", "zeta")
+	alpha := Define("sorted_alpha", "alpha")
+	mid := Define("sorted_mid", "mid")
+	for _, fn := range []func(...interface{}) *errors.Error{zeta, alpha, mid, alpha} {
+		fn().Free()
+	}
+
+	names := Names()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("Names() = %v, want sorted", names)
+	}
+
+	want := []MetricCount{{"sorted_alpha", 2}, {"sorted_mid", 1}, {"sorted_zeta", 1}}
+	got := MetricsSorted()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MetricsSorted() = %v, want %v", got, want)
+	}
+}
+
 func TestCountReset(t *testing.T) {
 	name := "test_reset"
 	ResetCounter(name)
@@ -117,3 +142,180 @@ func TestCountReset(t *testing.T) {
 		t.Errorf("Metrics()[%s] after reset = %d, want 1", name, Metrics()[name])
 	}
 }
+
+func TestSetSink(t *testing.T) {
+	var got []string
+	SetSink(func(err *errors.Error) {
+		got = append(got, err.Name()+": "+err.Error())
+	})
+	defer SetSink(nil)
+
+	tmpl := Coded("test_sink", "sink error: %s", 502)
+	err := tmpl("upstream")
+	defer err.Free()
+	tracked := Tracked("test_sink_tracked", func(args ...interface{}) *errors.Error {
+		return errors.Named("test_sink_tracked").Msgf("tracked %v", args[0])
+	})
+	err2 := tracked("call")
+	defer err2.Free()
+
+	want := []string{"test_sink: sink error: upstream", "test_sink_tracked: tracked call"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("sink received %v, want %v", got, want)
+	}
+
+	Configure(Config{DisableMetrics: true})
+	err3 := tmpl("disabled")
+	err3.Free()
+	Configure(Config{DisableMetrics: false})
+	if len(got) != 2 {
+		t.Errorf("sink called while disabled, got %d calls, want 2", len(got))
+	}
+
+	SetSink(nil)
+	err4 := tmpl("removed")
+	err4.Free()
+	if len(got) != 2 {
+		t.Errorf("sink called after removal, got %d calls, want 2", len(got))
+	}
+}
+
+type testRequestIDKey struct{}
+
+func TestDefineCtx(t *testing.T) {
+	SetContextKey(testRequestIDKey{}, "request_id")
+	ResetCounter("test_ctx")
+	tmpl := DefineCtx("test_ctx", "lookup of %s failed")
+
+	ctx := context.WithValue(context.Background(), testRequestIDKey{}, "req-42")
+	err := tmpl(ctx, "user42")
+	defer err.Free()
+	if err.Error() != "lookup of user42 failed" {
+		t.Errorf("DefineCtx() error = %v, want %v", err.Error(), "lookup of user42 failed")
+	}
+	if got := err.Context()["request_id"]; got != "req-42" {
+		t.Errorf("DefineCtx() request_id = %v, want req-42", got)
+	}
+	if Metrics()["test_ctx"] != 1 {
+		t.Errorf("Metrics()[test_ctx] = %d, want 1", Metrics()["test_ctx"])
+	}
+
+	bare := tmpl(context.Background(), "user43")
+	defer bare.Free()
+	if bare.HasContextKey("request_id") {
+		t.Errorf("DefineCtx() without the value should not set request_id, got %v", bare.Context())
+	}
+}
+
+func TestDefineStrict(t *testing.T) {
+	tmpl := DefineStrict("test_strict", "user %s not found in %d%%")
+
+	err := tmpl("alice", 3)
+	if err.Error() != "user alice not found in 3%" {
+		t.Errorf("DefineStrict() error = %q, want %q", err.Error(), "user alice not found in 3%")
+	}
+	err.Free()
+
+	err = tmpl()
+	defer err.Free()
+	want := `test_strict: template "user %s not found in %d%%" expects 2 argument(s), got 0`
+	if err.Error() != want {
+		t.Errorf("DefineStrict() mismatch error = %q, want %q", err.Error(), want)
+	}
+	if !err.HasContextKey("template_mismatch") || err.Name() != "test_strict" {
+		t.Errorf("DefineStrict() mismatch should be marked and keep its name, got %v", err.Context())
+	}
+
+	for template, want := range map[string]int{
+		"plain":        0,
+		"%v and %+v":   2,
+		"%*d|%-8.*f":   4,
+		"100%%":        0,
+		"%[1]s %[1]s":  -1,
+		"trailing %":   0,
+		"%5.2f %#x %q": 3,
+		"% d %08b %-s": 3,
+	} {
+		if got := templateArgs(template); got != want {
+			t.Errorf("templateArgs(%q) = %d, want %d", template, got, want)
+		}
+	}
+}
+
+func TestLastErrors(t *testing.T) {
+	Configure(Config{TrackLastErrors: true})
+	defer Configure(Config{DisableMetrics: false})
+	Reset()
+
+	dbErr := Define("test_last_db", "query %d failed")
+	netErr := Coded("test_last_net", "dial %s failed", 503)
+	for i := 1; i <= 3; i++ {
+		dbErr(i).Free() // The registry keeps its own copy
+	}
+	netErr("10.0.0.1").Free()
+
+	last := LastErrors()
+	if len(last) != 2 {
+		t.Fatalf("LastErrors() has %d entries, want 2: %v", len(last), last)
+	}
+	if got := last["test_last_db"].Error(); got != "query 3 failed" {
+		t.Errorf("LastErrors()[test_last_db] = %q, want the latest %q", got, "query 3 failed")
+	}
+	if got := last["test_last_net"]; got.Error() != "dial 10.0.0.1 failed" || got.Code() != 503 {
+		t.Errorf("LastErrors()[test_last_net] = %q (code %d)", got.Error(), got.Code())
+	}
+	if got := GetLastError("test_last_db"); got == nil || got == last["test_last_db"] {
+		t.Errorf("GetLastError() should return a fresh copy, got %v", got)
+	}
+	if GetLastError("test_last_missing") != nil {
+		t.Error("GetLastError() for an unknown name should be nil")
+	}
+
+	Reset()
+	if len(LastErrors()) != 0 {
+		t.Error("Reset() should clear last errors")
+	}
+	Configure(Config{})
+	dbErr(4).Free()
+	if GetLastError("test_last_db") != nil {
+		t.Error("Last errors should not be tracked when TrackLastErrors is off")
+	}
+}
+
+func TestMinInterval(t *testing.T) {
+	Configure(Config{TrackLastErrors: true})
+	defer Configure(Config{DisableMetrics: false})
+	Reset()
+	clock := time.Unix(0, 0)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	SetMinInterval("test_interval", 100*time.Millisecond)
+	defer SetMinInterval("test_interval", 0)
+	errFunc := Define("test_interval", "loop %d failed")
+
+	// 1000 errors over one second, one per millisecond.
+	for i := 0; i < 1000; i++ {
+		errFunc(i).Free()
+		clock = clock.Add(time.Millisecond)
+	}
+	if got := Metrics()["test_interval"]; got != 10 {
+		t.Errorf("Count after a 1s burst = %d, want 10", got)
+	}
+	if got := Coalesced("test_interval"); got != 990 {
+		t.Errorf("Coalesced() = %d, want 990", got)
+	}
+	if got := GetLastError("test_interval"); got == nil || got.Error() != "loop 900 failed" {
+		t.Errorf("GetLastError() = %v, want the last counted error", got)
+	}
+
+	SetMinInterval("test_interval", 0)
+	errFunc(1000).Free()
+	errFunc(1001).Free()
+	if got := Metrics()["test_interval"]; got != 12 {
+		t.Errorf("Count after removing the interval = %d, want 12", got)
+	}
+	if Coalesced("test_interval") != 0 {
+		t.Error("Coalesced() should be 0 once the interval is removed")
+	}
+}
@@ -4,20 +4,49 @@ package errmgr
 import (
 	"github.com/olekukonko/errors"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
-	monitorSize = 10
+	monitorSize   = 10
+	subscribeSize = 64
 )
 
+// subscriptions holds every active Subscribe channel.
+var subscriptions = subscriptionRegistry{byName: make(map[string]map[*subscriber]struct{})}
+
 // alertChannel wraps a channel with synchronization for safe closure.
 // Used internally by Monitor to manage alert delivery.
 type alertChannel struct {
 	ch     chan *errors.Error
+	events chan Alert // Created by the first Events call; nil until then
 	closed bool
 	mu     sync.Mutex
 }
 
+// close closes the alert channels. Idempotent.
+func (ac *alertChannel) close() {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	if ac.closed {
+		return
+	}
+	close(ac.ch)
+	if ac.events != nil {
+		close(ac.events)
+	}
+	ac.closed = true
+}
+
+// Alert describes a single monitor alert with its count in a typed field.
+type Alert struct {
+	Name  string        // Error name the alert is for
+	Count uint64        // Occurrences that triggered it: the total, or the window's count for rate thresholds
+	Err   *errors.Error // A copy of the alert error delivered on Alerts; its Count() equals Count
+	Time  time.Time     // When the alert was raised
+}
+
 // Monitor represents an error monitoring channel for a specific error name.
 // It receives alerts when the error count exceeds a configured threshold set via SetThreshold.
 type Monitor struct {
@@ -37,6 +66,22 @@ func (m *Monitor) Alerts() <-chan *errors.Error {
 	return m.ac.ch
 }
 
+// Events returns a channel receiving every alert as a typed Alert, so the count
+// is available without inspecting the error. It receives the same alerts as
+// Alerts, each channel buffered and dropped-when-full independently, and is
+// closed with the monitor. Returns nil if the monitor has been closed.
+func (m *Monitor) Events() <-chan Alert {
+	m.ac.mu.Lock()
+	defer m.ac.mu.Unlock()
+	if m.ac.closed {
+		return nil
+	}
+	if m.ac.events == nil {
+		m.ac.events = make(chan Alert, cap(m.ac.ch))
+	}
+	return m.ac.events
+}
+
 // Close shuts down the monitor channel and removes it from the registry.
 // Thread-safe and idempotent; subsequent calls have no effect.
 func (m *Monitor) Close() {
@@ -45,12 +90,7 @@ func (m *Monitor) Close() {
 
 	if existing, ok := registry.alerts.Load(m.name); ok {
 		if ac, ok := existing.(*alertChannel); ok && ac == m.ac {
-			ac.mu.Lock()
-			if !ac.closed {
-				close(ac.ch)
-				ac.closed = true
-			}
-			ac.mu.Unlock()
+			ac.close()
 			registry.alerts.Delete(m.name)
 		}
 	}
@@ -105,3 +145,145 @@ func NewMonitorBuffered(name string, buffer int) *Monitor {
 	registry.alerts.Store(name, ac)
 	return &Monitor{name: name, ac: ac}
 }
+
+// subscriber is a single Subscribe channel.
+type subscriber struct {
+	ch   chan *errors.Error
+	once sync.Once
+}
+
+// subscriptionRegistry maps error names to subscribers; the empty name
+// holds subscribers to every error.
+type subscriptionRegistry struct {
+	byName  map[string]map[*subscriber]struct{}
+	active  atomic.Int64  // Number of subscribers; lets publish skip locking when zero
+	dropped atomic.Uint64 // Events dropped because a subscriber's buffer was full
+	mu      sync.RWMutex  // Protects byName and channel closure
+}
+
+// Subscribe returns a channel receiving a copy of every error created for name
+// by Define, Coded, Categorized, or Tracked; an empty name subscribes to all
+// errors. The returned function unsubscribes and closes the channel; it is
+// safe to call multiple times. Like threshold alerts, events are dropped when
+// the buffer of 64 is full (see DroppedEvents). Nothing is delivered while
+// metrics are disabled.
+//
+// Example:
+//
+//	events, cancel := errmgr.Subscribe("ErrDBQuery")
+//	defer cancel()
+//	for err := range events {
+//	    log.Println(err)
+//	}
+func Subscribe(name string) (<-chan *errors.Error, func()) {
+	sub := &subscriber{ch: make(chan *errors.Error, subscribeSize)}
+
+	subscriptions.mu.Lock()
+	set, ok := subscriptions.byName[name]
+	if !ok {
+		set = make(map[*subscriber]struct{})
+		subscriptions.byName[name] = set
+	}
+	set[sub] = struct{}{}
+	subscriptions.active.Add(1)
+	subscriptions.mu.Unlock()
+
+	return sub.ch, func() { subscriptions.remove(name, sub) }
+}
+
+// DroppedEvents returns the total number of subscription events dropped
+// because a subscriber's channel was full.
+func DroppedEvents() uint64 {
+	return subscriptions.dropped.Load()
+}
+
+// remove unregisters sub and closes its channel. Idempotent.
+func (r *subscriptionRegistry) remove(name string, sub *subscriber) {
+	sub.once.Do(func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if set, ok := r.byName[name]; ok {
+			delete(set, sub)
+			if len(set) == 0 {
+				delete(r.byName, name)
+			}
+		}
+		r.active.Add(-1)
+		close(sub.ch)
+	})
+}
+
+// publish delivers a copy of err to subscribers of name and of all errors.
+// Never blocks; full channels drop the event and increment the drop count.
+func (r *subscriptionRegistry) publish(name string, err *errors.Error) {
+	if r.active.Load() == 0 {
+		return
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.deliver(r.byName[name], err)
+	if name != "" {
+		r.deliver(r.byName[""], err)
+	}
+}
+
+// deliver sends a private copy of err to each subscriber in set, so callers
+// may Free their error without affecting subscribers. Caller must hold r.mu.
+func (r *subscriptionRegistry) deliver(set map[*subscriber]struct{}, err *errors.Error) {
+	for sub := range set {
+		cp := err.Copy()
+		select {
+		case sub.ch <- cp:
+		default:
+			cp.Free()
+			r.dropped.Add(1)
+		}
+	}
+}
+
+// MetricsStream returns a channel receiving a fresh snapshot of Metrics every
+// interval, for exporters and live dashboards that would otherwise poll. The
+// channel holds only the latest snapshot: one the reader has not taken yet is
+// replaced by the next. Snapshots are never nil, but are empty when no error has
+// been counted or metrics are disabled. The returned function stops the stream
+// and closes the channel; it is safe to call multiple times. A non-positive
+// interval returns a closed channel.
+//
+// Example:
+//
+//	snapshots, stop := errmgr.MetricsStream(10 * time.Second)
+//	defer stop()
+//	for counts := range snapshots {
+//	    exporter.Push(counts)
+//	}
+func MetricsStream(interval time.Duration) (<-chan map[string]uint64, func()) {
+	ch := make(chan map[string]uint64, 1)
+	if interval <= 0 {
+		close(ch)
+		return ch, func() {}
+	}
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			snapshot := Metrics()
+			if snapshot == nil {
+				snapshot = make(map[string]uint64)
+			}
+			select {
+			case <-ch: // Replace a snapshot the reader has not taken
+			default:
+			}
+			ch <- snapshot
+		}
+	}()
+	return ch, func() { once.Do(func() { close(done) }) }
+}
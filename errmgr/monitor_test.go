@@ -1,6 +1,8 @@
 package errmgr
 
 import (
+	"fmt"
+	"github.com/olekukonko/errors"
 	"strings"
 	"sync"
 	"testing"
@@ -41,6 +43,59 @@ func TestMonitorAlerts(t *testing.T) {
 	}
 }
 
+func TestMonitorEvents(t *testing.T) {
+	Reset()
+	monitor := NewMonitorBuffered("EventError", 5)
+	SetThreshold("EventError", 2)
+	defer RemoveThreshold("EventError")
+	events := monitor.Events()
+	if monitor.Events() != events {
+		t.Error("Expected Events to return the same channel on each call")
+	}
+
+	errFunc := Define("EventError", "event error %d")
+	for i := 0; i < 2; i++ {
+		errFunc(i).Free()
+	}
+
+	var ev Alert
+	select {
+	case ev = <-events:
+		if ev.Name != "EventError" {
+			t.Errorf("Expected event name 'EventError', got %q", ev.Name)
+		}
+		if ev.Count != 2 {
+			t.Errorf("Expected event count 2, got %d", ev.Count)
+		}
+		if ev.Err == nil || ev.Err.Count() != ev.Count {
+			t.Errorf("Expected event error with count %d, got %v", ev.Count, ev.Err)
+		}
+		if ev.Time.IsZero() {
+			t.Error("Expected event time to be set")
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("No event received within 100ms timeout")
+	}
+	alert := <-monitor.Alerts()
+	if alert.Count() != 2 {
+		t.Errorf("Expected alert count 2, got %d", alert.Count())
+	}
+	if alert == ev.Err {
+		t.Error("Expected Events and Alerts to carry separate copies")
+	}
+	alert.Free()
+	if ev.Err.Count() != 2 || ev.Err.Error() == "" {
+		t.Errorf("Freeing the Alerts error changed the event's: %q (count %d)", ev.Err.Error(), ev.Err.Count())
+	}
+
+	monitor.Close()
+	for range events {
+	}
+	if monitor.Events() != nil {
+		t.Error("Expected nil Events channel after Close")
+	}
+}
+
 func TestMonitorBuffered(t *testing.T) {
 	Reset()
 	monitor := NewMonitorBuffered("BufferedError", 2) // Buffer size 2
@@ -201,3 +256,186 @@ func TestMonitorMultipleInstances(t *testing.T) {
 		t.Error("No alert received from monitor2 within timeout")
 	}
 }
+
+func TestSeverityAlert(t *testing.T) {
+	Reset()
+	monitor := NewMonitor("SevError")
+	defer monitor.Close()
+	SetSeverityAlert("SevError", errors.SeverityCritical)
+	defer SetSeverityAlert("SevError", errors.SeverityNone)
+
+	create := Tracked("SevError", func(args ...interface{}) *errors.Error {
+		return errors.New("disk failure").WithSeverity(args[0].(errors.Severity))
+	})
+
+	create(errors.SeverityWarning).Free()
+	select {
+	case alert := <-monitor.Alerts():
+		t.Fatalf("Unexpected alert for a warning: %v", alert)
+	default:
+	}
+
+	create(errors.SeverityFatal).Free()
+	select {
+	case alert := <-monitor.Alerts():
+		if alert.Severity() != errors.SeverityFatal {
+			t.Errorf("Expected fatal alert, got %v", alert.Severity())
+		}
+		if !strings.Contains(alert.Error(), "reached severity fatal: disk failure") {
+			t.Errorf("Expected severity message wrapping the error, got %q", alert.Error())
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("No alert received for a fatal error")
+	}
+}
+
+func TestRateThreshold(t *testing.T) {
+	Reset()
+	clock := time.Unix(0, 0)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	monitor := NewMonitorBuffered("RateError", 10)
+	defer monitor.Close()
+	SetRateThreshold("RateError", 3, time.Minute)
+	defer RemoveThreshold("RateError")
+	errFunc := Define("RateError", "rate error %d")
+
+	burst := func(n int) {
+		for i := 0; i < n; i++ {
+			errFunc(i).Free()
+			clock = clock.Add(time.Second)
+		}
+	}
+	alerts := func() int {
+		n := 0
+		for {
+			select {
+			case alert := <-monitor.Alerts():
+				if !strings.Contains(alert.Error(), "rate exceeded") {
+					t.Errorf("Expected rate alert, got %q", alert.Error())
+				}
+				n++
+			default:
+				return n
+			}
+		}
+	}
+
+	burst(3)
+	if n := alerts(); n != 0 {
+		t.Errorf("Expected no alert at the limit, got %d", n)
+	}
+	burst(5)
+	if n := alerts(); n != 1 {
+		t.Errorf("Expected one alert per window, got %d", n)
+	}
+
+	clock = clock.Add(time.Minute)
+	burst(2)
+	if n := alerts(); n != 0 {
+		t.Errorf("Expected no alert below the limit in a new window, got %d", n)
+	}
+
+	clock = clock.Add(time.Minute)
+	burst(4)
+	if n := alerts(); n != 1 {
+		t.Errorf("Expected the alert to re-arm in a new window, got %d", n)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	events, cancel := Subscribe("SubscribedError")
+	all, cancelAll := Subscribe("")
+	defer cancelAll()
+
+	errFunc := Coded("SubscribedError", "subscribed error %d", 409)
+	for i := 0; i < 3; i++ {
+		errFunc(i).Free() // Subscribers receive copies, so freeing is safe
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-events:
+			want := fmt.Sprintf("subscribed error %d", i)
+			if err.Error() != want {
+				t.Errorf("Expected %q, got %q", want, err.Error())
+			}
+			if err.Code() != 409 {
+				t.Errorf("Expected code 409, got %d", err.Code())
+			}
+		case <-time.After(100 * time.Millisecond):
+			t.Fatalf("Event %d not received", i)
+		}
+	}
+
+	received := 0
+	for received < 3 {
+		select {
+		case err := <-all:
+			if err.Name() == "SubscribedError" {
+				received++
+			}
+		case <-time.After(100 * time.Millisecond):
+			t.Fatalf("Wildcard subscriber received %d of 3 events", received)
+		}
+	}
+
+	cancel()
+	cancel() // Idempotent
+	if _, ok := <-events; ok {
+		t.Error("Expected channel to be closed after unsubscribe")
+	}
+	errFunc(99).Free() // Must not panic after unsubscribe
+}
+
+func TestSubscribeDropsWhenFull(t *testing.T) {
+	events, cancel := Subscribe("FloodError")
+	defer cancel()
+
+	before := DroppedEvents()
+	errFunc := Define("FloodError", "flood %d")
+	for i := 0; i < subscribeSize+5; i++ {
+		errFunc(i).Free()
+	}
+	if got := DroppedEvents() - before; got != 5 {
+		t.Errorf("Expected 5 dropped events, got %d", got)
+	}
+	if len(events) != subscribeSize {
+		t.Errorf("Expected %d buffered events, got %d", subscribeSize, len(events))
+	}
+}
+
+func TestMetricsStream(t *testing.T) {
+	Reset()
+	errFunc := Define("StreamError", "stream %d")
+	snapshots, stop := MetricsStream(10 * time.Millisecond)
+
+	errFunc(1).Free()
+	first := <-snapshots
+	if first == nil {
+		t.Fatal("Expected a non-nil snapshot")
+	}
+	errFunc(2).Free()
+	errFunc(3).Free()
+	deadline := time.After(time.Second)
+	for got := first["StreamError"]; got < 3; {
+		select {
+		case counts := <-snapshots:
+			got = counts["StreamError"]
+		case <-deadline:
+			t.Fatalf("No snapshot reflected the new errors, last count %d", got)
+		}
+	}
+
+	stop()
+	stop() // Idempotent
+	for range snapshots {
+		// Drain a snapshot sent before stop
+	}
+
+	closed, _ := MetricsStream(0)
+	if _, ok := <-closed; ok {
+		t.Error("Expected a closed channel for a non-positive interval")
+	}
+}
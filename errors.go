@@ -11,8 +11,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"log/slog"
+	"regexp"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -20,7 +25,8 @@ import (
 
 // Error is a custom error type with enhanced features: message, name, stack trace,
 // context, cause, and metadata like code and category. It is thread-safe and
-// supports pooling for performance.
+// supports pooling for performance. Methods are safe to call on a nil *Error:
+// accessors return zero values and modifiers do nothing and return nil.
 type Error struct {
 	// Fields used in atomic operations. Place them at the beginning of the
 	// struct to ensure proper alignment across all architectures.
@@ -29,31 +35,58 @@ type Error struct {
 	// Primary fields (frequently accessed).
 	msg   string    // The error message displayed by Error().
 	name  string    // The error name or type (e.g., "AuthError").
+	op    string    // Operation set by WithOp (e.g., "users.Create"), prefixed by Error().
 	stack []uintptr // Stack trace as program counters.
 
+	// stackStrings holds opaque frames set via SetStackStrings (e.g., from a
+	// remote error); when present, Stack() returns them instead of decoding stack.
+	stackStrings []string
+
+	// hiddenFrames holds function name prefixes set by HideFrames; matching
+	// frames are left out of this error's Stack and FastStack.
+	hiddenFrames []string
+
 	// Secondary metadata.
-	template   string // Fallback message template if msg is empty.
-	category   string // Error category (e.g., "network").
-	code       int32  // HTTP-like status code (e.g., 400, 500).
-	smallCount int32  // Number of items in smallContext.
+	template   string     // Fallback message template if msg is empty.
+	category   string     // Error category (e.g., "network").
+	code       int32      // HTTP-like status code (e.g., 400, 500).
+	smallCount int32      // Number of items in smallContext.
+	severity   Severity   // Seriousness set by WithSeverity; SeverityNone if unset.
+	tags       []string   // Labels set by WithTags, in insertion order without duplicates.
+	logLevel   slog.Level // Level set by WithLogLevel; meaningful only if hasLogLevel.
 
 	// Context and chaining.
 	context      map[string]interface{}   // Key-value pairs for additional context.
 	cause        error                    // Wrapped underlying error for chaining.
 	callback     func()                   // Optional callback invoked by Error().
+	onError      func(*Error)             // Optional callback receiving the error, invoked by Error().
 	smallContext [contextSize]contextItem // Fixed-size array for small contexts.
 
 	// Synchronization.
 	mu sync.RWMutex // Protects mutable fields (context, smallContext).
 
 	// Internal flags.
-	formatWrapped bool // True if created by Newf with %w verb.
+	formatWrapped bool  // True if created by Newf with %w verb.
+	hasCode       bool  // True once WithCode has been called, distinguishing an explicit 0 from unset.
+	hasLogLevel   bool  // True once WithLogLevel has been called, since slog.LevelInfo is 0.
+	frozen        int32 // Non-zero once Freeze has been called; modifiers then work on a copy.
 }
 
-// newError creates a new Error instance, reusing from the pool if enabled.
-// Initializes smallContext and sets stack to nil.
+// newError creates a new Error instance, reusing from the pool if enabled, and
+// records the creating goroutine if Config.CaptureGoroutineID is set.
 // Internal use; prefer New, Named, or Trace for public API.
 func newError() *Error {
+	e := allocError()
+	if currentConfig.goroutineID {
+		e.smallContext[0] = contextItem{ctxGoroutine, goroutineID()}
+		e.smallCount = 1
+	}
+	return e
+}
+
+// allocError returns a blank Error from the pool, or a new one if pooling is
+// disabled. Initializes smallContext and sets stack to nil.
+func allocError() *Error {
 	if currentConfig.disablePooling {
 		return &Error{
 			smallContext: [contextSize]contextItem{},
@@ -370,8 +403,36 @@ func Tracef(format string, args ...interface{}) *Error {
 	return e.WithStack()
 }
 
+// Annotate prepends msg and ": " to the error’s message in place and returns the error.
+// Cheaper than wrapping when no separate chain node is needed. If the error has no
+// message, its template or name is used as the base, mirroring Error().
+// Example:
+//
+//	err := errors.New("connection refused").Annotate("fetch user") // "fetch user: connection refused"
+func (e *Error) Annotate(msg string) *Error {
+	if e == nil || msg == "" {
+		return e
+	}
+	e = e.mutable()
+	base := e.msg
+	if base == "" && !e.formatWrapped {
+		if e.template != "" {
+			base = e.template
+		} else {
+			base = e.name
+		}
+	}
+	if base == "" {
+		e.msg = msg
+	} else {
+		e.msg = msg + ": " + base
+	}
+	return e
+}
+
 // As attempts to assign the error or one in its chain to the target interface.
-// Supports *Error and standard error types, traversing the cause chain.
+// Supports *Error and standard error types, traversing the cause chain; other
+// targets, such as **MultiError, are matched against the cause via errors.As.
 // Returns true if successful.
 // Example:
 //
@@ -435,16 +496,79 @@ func (e *Error) As(target interface{}) bool {
 	return false
 }
 
+// Because sets cause as the error’s cause and returns the error. It is an alias
+// for Wrap that reads more naturally in fluent construction; like Wrap, it
+// leaves a frozen error unchanged and returns a wrapping copy.
+// Example:
+//
+//	err := errors.New("login failed").Because(dbErr)
+func (e *Error) Because(cause error) *Error {
+	return e.Wrap(cause)
+}
+
 // Callback sets a function to be called when Error() is invoked.
 // Useful for logging or side effects on error access.
 // Example:
 //
 //	err := errors.New("test").Callback(func() { log.Println("error accessed") })
 func (e *Error) Callback(fn func()) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.callback = fn
 	return e
 }
 
+// OnError sets a function to be called with the error when Error() is invoked.
+// Unlike Callback, fn receives a copy of the error so hooks can read its fields;
+// calling Error() on it from within fn is safe and re-triggers neither the hook
+// nor Callback. The hook runs on every call, including concurrent ones, and each
+// call makes a pooled copy of the error (context, tags, and stack included) that
+// is freed when fn returns, so fn must not keep it; use Copy to retain one.
+// Example:
+//
+//	err := errors.New("test").OnError(func(e *errors.Error) { log.Println(e.Error(), e.Code()) })
+func (e *Error) OnError(fn func(*Error)) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	e.onError = fn
+	return e
+}
+
+// Caller returns the creation site recorded by WithCaller as "file:line:function",
+// or an empty string if none was recorded.
+// Example:
+//
+//	log.Println(err.Caller()) // e.g., "/app/main.go:42:main.run"
+func (e *Error) Caller() string {
+	if e == nil {
+		return ""
+	}
+	if v, ok := e.contextValue(ctxCaller); ok {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// CanonicalJSON returns the error’s JSON encoding in a stable form for golden
+// files and snapshot tests: equal errors always produce identical bytes. Context
+// keys, including those of nested maps and wrapped *Error causes, are sorted and
+// the stack keeps its frame order. The output currently matches MarshalJSON,
+// which encodes context through encoding/json’s sorted maps; CanonicalJSON makes
+// that ordering a guarantee callers can rely on.
+// Example:
+//
+//	got, _ := err.CanonicalJSON()
+//	want, _ := os.ReadFile("testdata/err.golden.json")
+func (e *Error) CanonicalJSON() ([]byte, error) {
+	return e.MarshalJSON()
+}
+
 // Category returns the error’s category, if set.
 // Example:
 //
@@ -452,9 +576,23 @@ func (e *Error) Callback(fn func()) *Error {
 //	  handleNetworkError(err)
 //	}
 func (e *Error) Category() string {
+	if e == nil {
+		return ""
+	}
 	return e.category
 }
 
+// IsCategory reports whether the error’s own category is cat.
+// Causes are not checked; use HasCategory to search the whole chain.
+// Example:
+//
+//	if err.IsCategory("database") {
+//	  retryWithReplica()
+//	}
+func (e *Error) IsCategory(cat ErrorCategory) bool {
+	return e != nil && e.category == string(cat)
+}
+
 // Code returns the error’s HTTP-like status code, if set.
 // Returns 0 if no code is set.
 // Example:
@@ -463,11 +601,81 @@ func (e *Error) Category() string {
 //	  renderNotFound()
 //	}
 func (e *Error) Code() int {
+	if e == nil {
+		return 0
+	}
+	return int(e.code)
+}
+
+// CodeOrDefault returns the error’s code, or def if WithCode was never called.
+// Unlike Code, an explicit WithCode(0) returns 0 rather than def.
+// Example:
+//
+//	status := err.CodeOrDefault(http.StatusInternalServerError)
+func (e *Error) CodeOrDefault(def int) int {
+	if e == nil || !e.hasCode {
+		return def
+	}
 	return int(e.code)
 }
 
+// CodeClass returns the class of the error’s code, its hundreds digit: 4 for
+// 4xx, 5 for 5xx, and so on. Returns 0 if no code is set.
+// Example:
+//
+//	metrics.Inc(fmt.Sprintf("errors_%dxx", err.CodeClass()))
+func (e *Error) CodeClass() int {
+	return e.Code() / 100
+}
+
+// CodeChain returns the code of the first error set with WithCode found walking
+// the error and its causes, or 0 if none has one. Unlike Code, it sees the code
+// of a wrapped error when the wrapper has none; an explicit WithCode(0) counts.
+// Example:
+//
+//	err := errors.New("request failed").Wrap(ErrNotFound) // ErrNotFound has code 404
+//	err.CodeChain() // 404
+func (e *Error) CodeChain() int {
+	code := 0
+	e.WalkUntil(func(err error) bool {
+		if ce, ok := err.(*Error); ok && ce.hasCode {
+			code = int(ce.code)
+			return false
+		}
+		return true
+	})
+	return code
+}
+
+// Compare orders errors by priority, returning -1 if e comes before other, 1 if
+// after, and 0 if equal: higher severity first, then higher code, then name in
+// ascending order. A nil error sorts after any non-nil one.
+// Example:
+//
+//	sort.Slice(errs, func(i, j int) bool { return errs[i].Compare(errs[j]) < 0 })
+func (e *Error) Compare(other *Error) int {
+	switch {
+	case e == nil && other == nil:
+		return 0
+	case e == nil:
+		return 1
+	case other == nil:
+		return -1
+	case e.severity != other.severity:
+		return cmpDesc(int(e.severity), int(other.severity))
+	case e.code != other.code:
+		return cmpDesc(int(e.code), int(other.code))
+	case e.name < other.name:
+		return -1
+	case e.name > other.name:
+		return 1
+	}
+	return 0
+}
+
 // Context returns the error’s context as a map, merging smallContext and map-based context.
-// Thread-safe; lazily initializes the map if needed.
+// Thread-safe; lazily initializes the map if needed. A frozen error returns a copy,
+// so changing the map does not change the error.
 // Example:
 //
 //	ctx := err.Context()
@@ -475,16 +683,105 @@ func (e *Error) Code() int {
 //	  fmt.Println(userID)
 //	}
 func (e *Error) Context() map[string]interface{} {
+	if e == nil {
+		return nil
+	}
 	e.mu.RLock()
-	defer e.mu.RUnlock()
+	ctx, built := e.context, e.smallCount == 0 || e.context != nil
+	e.mu.RUnlock()
+	if !built {
+		// Building the map writes to e, so take the write lock and let
+		// concurrent readers that raced here share the first one's map.
+		e.mu.Lock()
+		e.buildContextMap()
+		ctx = e.context
+		e.mu.Unlock()
+	}
+	if ctx != nil && e.IsFrozen() {
+		copied := make(map[string]interface{}, len(ctx))
+		for k, v := range ctx {
+			copied[k] = v
+		}
+		return copied
+	}
+	return ctx
+}
 
+// buildContextMap fills e.context from smallContext if it has not been built yet.
+// Caller must hold e.mu.
+func (e *Error) buildContextMap() {
 	if e.smallCount > 0 && e.context == nil {
 		e.context = make(map[string]interface{}, e.smallCount)
 		for i := int32(0); i < e.smallCount; i++ {
 			e.context[e.smallContext[i].key] = e.smallContext[i].value
 		}
 	}
-	return e.context
+}
+
+// ContextJSON returns only the error’s own context as a JSON object, encoded
+// exactly like the "context" field of MarshalJSON: keys sorted, HTML not
+// escaped, and long values cut per Config.MaxJSONValueLen. Returns {} if the
+// context is empty. Cheaper than marshaling the whole error for logging.
+// Example:
+//
+//	data, _ := err.ContextJSON()
+//	logger.Info("request failed", "context", json.RawMessage(data))
+func (e *Error) ContextJSON() ([]byte, error) {
+	ctx := e.Context()
+	if len(ctx) == 0 {
+		return []byte("{}"), nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(truncateContextValues(ctx, currentConfig.maxJSONLen)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// ContextKeys returns the error’s context keys in sorted order, without building
+// the context map that Context() materializes. Returns nil if there is no context.
+// Thread-safe; only keys at this level are included, not those of wrapped causes.
+// Example:
+//
+//	for _, key := range err.ContextKeys() {
+//	  fmt.Println(key)
+//	}
+func (e *Error) ContextKeys() []string {
+	if e == nil {
+		return nil
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.smallCount == 0 && len(e.context) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, int(e.smallCount)+len(e.context))
+	for k := range e.context {
+		keys = append(keys, k)
+	}
+	mapKeys := len(keys)
+	for i := int32(0); i < e.smallCount; i++ {
+		// With does not deduplicate smallContext, and a lazily built map repeats it.
+		key := e.smallContext[i].key
+		if _, inMap := e.context[key]; inMap {
+			continue
+		}
+		seen := false
+		for _, k := range keys[mapKeys:] {
+			if k == key {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // Copy creates a deep copy of the error, preserving all fields except stack freshness.
@@ -493,23 +790,35 @@ func (e *Error) Context() map[string]interface{} {
 //
 //	newErr := err.Copy().With("new_key", "value")
 func (e *Error) Copy() *Error {
+	if e == nil {
+		return nil
+	}
 	if e == emptyError {
 		return &Error{
 			smallContext: [contextSize]contextItem{},
 		}
 	}
 
-	newErr := newError()
+	newErr := allocError()
 
 	newErr.msg = e.msg
 	newErr.name = e.name
+	newErr.op = e.op
 	newErr.template = e.template
 	newErr.cause = e.cause
 	newErr.code = e.code
+	newErr.hasCode = e.hasCode
+	newErr.severity = e.severity
+	newErr.logLevel = e.logLevel
+	newErr.hasLogLevel = e.hasLogLevel
 	newErr.category = e.category
+	if len(e.tags) > 0 {
+		newErr.tags = append([]string(nil), e.tags...)
+	}
 	newErr.count = e.count
 	newErr.callback = e.callback           // was silently dropped by Copy
 	newErr.formatWrapped = e.formatWrapped // was silently dropped by Copy
+	newErr.onError = e.onError
 
 	if e.smallCount > 0 {
 		newErr.smallCount = e.smallCount
@@ -523,6 +832,13 @@ func (e *Error) Copy() *Error {
 		}
 	}
 
+	if len(e.stackStrings) > 0 {
+		newErr.stackStrings = append([]string(nil), e.stackStrings...)
+	}
+	if len(e.hiddenFrames) > 0 {
+		newErr.hiddenFrames = append([]string(nil), e.hiddenFrames...)
+	}
+
 	if e.stack != nil && len(e.stack) > 0 {
 		if newErr.stack == nil {
 			newErr.stack = stackPool.Get().([]uintptr)
@@ -539,30 +855,98 @@ func (e *Error) Copy() *Error {
 //
 //	fmt.Printf("Error occurred %d times", err.Count())
 func (e *Error) Count() uint64 {
+	if e == nil {
+		return 0
+	}
 	return e.count
 }
 
+// Depth returns the number of errors in the chain followed by Unwrap, counting e
+// itself, so an error without a cause has depth 1. Cheaper than len(UnwrapAll()).
+// A chain that loops back to an *Error already seen stops there. Returns 0 for nil.
+// Example:
+//
+//	if err.Depth() > 10 {
+//	  log.Println("deeply wrapped error:", err)
+//	}
+func (e *Error) Depth() int {
+	if e == nil {
+		return 0
+	}
+	var seen map[*Error]struct{}
+	depth := 0
+	for current := error(e); current != nil; current = errors.Unwrap(current) {
+		if ce, ok := current.(*Error); ok && ce.cause != nil {
+			// Only *Error causes can be rewired into a loop; track them lazily.
+			if seen == nil {
+				seen = make(map[*Error]struct{})
+			}
+			if _, dup := seen[ce]; dup {
+				break
+			}
+			seen[ce] = struct{}{}
+		}
+		depth++
+	}
+	return depth
+}
+
 // Err returns the error as an error interface.
 // Useful for type assertions or interface compatibility.
 // Example:
 //
 //	var stdErr error = err.Err()
 func (e *Error) Err() error {
+	if e == nil {
+		return nil
+	}
 	return e
 }
 
+// ToStdError returns a plain error, as from the standard errors.New, carrying
+// only the composed Error() message. The chain, context, stack, and other
+// metadata are dropped, so nothing beyond the text crosses a trust boundary.
+// Returns nil for a nil receiver.
+// Example:
+//
+//	return nil, err.ToStdError()
+func (e *Error) ToStdError() error {
+	if e == nil {
+		return nil
+	}
+	return errors.New(e.Error())
+}
+
 // Error returns the string representation of the error.
 // If the error was created using Newf/Errorf with the %w verb, it returns the
 // pre-formatted string compatible with fmt.Errorf.
 // Otherwise, it combines the message, template, or name with the cause's error
-// string, separated by ": ". Invokes any set callback.
+// string, separated by ": " or Config.MessageSeparator. Invokes any set callback and OnError hook.
+// Returns "<nil>" for a nil receiver, as fmt prints nil pointers.
 func (e *Error) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
 	if e.callback != nil {
 		e.callback()
 	}
+	if e.onError != nil {
+		// The hook commonly calls Error() on what it receives; handing it a copy
+		// without hooks turns that nested call into a plain message read instead
+		// of recursion or a second Callback, and every concurrent caller still
+		// fires it. The copy goes back to the pool once the hook returns.
+		view := e.Copy()
+		view.onError = nil
+		view.callback = nil
+		e.onError(view)
+		view.Free()
+	}
 
 	// If created by Newf/Errorf with %w, msg already contains the final string.
 	if e.formatWrapped {
+		if e.op != "" {
+			return e.op + currentConfig.separator + e.msg
+		}
 		return e.msg // Return the pre-formatted fmt.Errorf-compatible string
 	}
 
@@ -570,20 +954,32 @@ func (e *Error) Error() string {
 	//  or errors created via New/Named and then Wrap() called.
 	var buf strings.Builder
 
+	// Prefix the operation, if set
+	if e.op != "" {
+		buf.WriteString(e.op)
+	}
+
 	// Append primary message part (msg, template, or name)
-	if e.msg != "" {
-		buf.WriteString(e.msg)
-	} else if e.template != "" {
-		buf.WriteString(e.template)
-	} else if e.name != "" {
-		buf.WriteString(e.name)
+	primary := e.msg
+	if primary == "" {
+		if e.template != "" {
+			primary = e.template
+		} else {
+			primary = e.name
+		}
+	}
+	if primary != "" {
+		if buf.Len() > 0 {
+			buf.WriteString(currentConfig.separator)
+		}
+		buf.WriteString(primary)
 	}
 
 	// Append cause if it exists (only relevant if not formatWrapped)
 	if e.cause != nil {
 		if buf.Len() > 0 {
 			// Add separator only if there was a prefix message/name/template
-			buf.WriteString(": ")
+			buf.WriteString(currentConfig.separator)
 		}
 		buf.WriteString(e.cause.Error())
 	} else if buf.Len() == 0 {
@@ -596,7 +992,7 @@ func (e *Error) Error() string {
 }
 
 // FastStack returns a lightweight stack trace with file and line numbers only.
-// Omits function names for performance; skips internal frames if configured.
+// Omits function names for performance; frames are filtered as configured (see Config.StackFilter).
 // Returns nil if no stack trace exists.
 // Example:
 //
@@ -604,14 +1000,17 @@ func (e *Error) Error() string {
 //	  fmt.Println(frame) // e.g., "main.go:42"
 //	}
 func (e *Error) FastStack() []string {
+	if e == nil {
+		return nil
+	}
+	if len(e.stackStrings) > 0 {
+		return append([]string(nil), e.stackStrings...)
+	}
 	// Same len-vs-nil reasoning as Stack().
 	if len(e.stack) == 0 {
 		return nil
 	}
-	configMu.RLock()
-	filter := currentConfig.filterInternal
-	configMu.RUnlock()
-
+	keep := e.frameFilter()
 	pcs := e.stack
 	frames := make([]string, 0, len(pcs))
 	for _, pc := range pcs {
@@ -621,7 +1020,7 @@ func (e *Error) FastStack() []string {
 			continue
 		}
 		file, line := fn.FileLine(pc)
-		if filter && isInternalFrame(runtime.Frame{File: file, Function: fn.Name()}) {
+		if keep != nil && !keep(runtime.Frame{PC: pc, Func: fn, Function: fn.Name(), File: file, Line: line}) {
 			continue
 		}
 		frames = append(frames, fmt.Sprintf("%s:%d", file, line))
@@ -655,6 +1054,9 @@ func (e *Error) Find(pred func(error) bool) error {
 //	// Stack:
 //	//   1. main.main main.go:42
 func (e *Error) Format() string {
+	if e == nil {
+		return ""
+	}
 	var sb strings.Builder
 
 	// Error message.
@@ -674,9 +1076,9 @@ func (e *Error) Format() string {
 	}
 
 	// Stack trace.
-	if e.stack != nil {
+	if stack := e.Stack(); len(stack) > 0 {
 		sb.WriteString("Stack:\n")
-		for i, frame := range e.Stack() {
+		for i, frame := range stack {
 			sb.WriteString(fmt.Sprintf("\t%d. %s\n", i+1, frame))
 		}
 	}
@@ -716,15 +1118,95 @@ func (e *Error) contextAtThisLevel() map[string]interface{} {
 	return ctx
 }
 
+// contextValue returns the value stored under key at this level, excluding
+// inherited context. The map takes precedence when present since it always
+// holds the latest values; otherwise smallContext is scanned newest-first.
+// Thread-safe.
+func (e *Error) contextValue(key string) (interface{}, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.context != nil {
+		v, ok := e.context[key]
+		return v, ok
+	}
+	for i := e.smallCount - 1; i >= 0; i-- {
+		if e.smallContext[i].key == key {
+			return e.smallContext[i].value, true
+		}
+	}
+	return nil, false
+}
+
+// removeContextKey deletes key from both smallContext and the map, keeping
+// the remaining smallContext items in order. Caller must hold e.mu.
+func (e *Error) removeContextKey(key string) {
+	n := int32(0)
+	for i := int32(0); i < e.smallCount; i++ {
+		if e.smallContext[i].key != key {
+			e.smallContext[n] = e.smallContext[i]
+			n++
+		}
+	}
+	for i := n; i < e.smallCount; i++ {
+		e.smallContext[i] = contextItem{}
+	}
+	e.smallCount = n
+	if e.context != nil {
+		delete(e.context, key)
+	}
+}
+
+// Freeze marks the error immutable and returns it, so it can be shared across
+// goroutines or kept as a package-level value. Modifiers such as With, Wrap, and
+// WithCode then leave it unchanged and return a modified copy instead; Free and
+// Reset do nothing, and Context returns a copy. Increment still counts on the
+// frozen error. Freeze the error before sharing it; there is no way to unfreeze it.
+// Example:
+//
+//	var ErrNotFound = errors.Named("NotFound").WithCode(404).Freeze()
+//	err := ErrNotFound.With("id", id) // A copy; ErrNotFound is unchanged
+func (e *Error) Freeze() *Error {
+	if e == nil {
+		return nil
+	}
+	// Build the context map now so later reads never write to the shared error.
+	e.mu.Lock()
+	e.buildContextMap()
+	e.mu.Unlock()
+	atomic.StoreInt32(&e.frozen, 1)
+	return e
+}
+
+// IsFrozen reports whether Freeze has been called on the error.
+func (e *Error) IsFrozen() bool {
+	return e != nil && atomic.LoadInt32(&e.frozen) != 0
+}
+
+// mutable returns e, or a copy of it if e is frozen, for modifiers to change.
+func (e *Error) mutable() *Error {
+	if e.IsFrozen() {
+		return e.Copy()
+	}
+	return e
+}
+
 // Free resets the error and returns it to the pool if pooling is enabled.
-// Safe to call multiple times; no-op if pooling is disabled.
-// Call after use to return the error to the pool and prevent memory leaks.
-// Use defer err.Free() at the call site that created the error.
+// With pooling disabled it still resets the error, dropping its references to
+// context values, causes, and callbacks so they can be collected even if the
+// error itself stays reachable. Safe to call multiple times; the error must not
+// be used afterwards. Frozen errors are left untouched. Use defer err.Free() at
+// the call site that created the error.
 // Example:
 //
 //	defer err.Free()
 func (e *Error) Free() {
+	if e == nil || e.IsFrozen() {
+		return
+	}
 	if currentConfig.disablePooling {
+		e.Reset()
+		e.stack = nil
 		return
 	}
 
@@ -761,6 +1243,9 @@ func (e *Error) Has() bool {
 //	  fmt.Println(err.Context()["user_id"])
 //	}
 func (e *Error) HasContextKey(key string) bool {
+	if e == nil {
+		return false
+	}
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
@@ -778,18 +1263,133 @@ func (e *Error) HasContextKey(key string) bool {
 	return false
 }
 
+// HasTag reports whether the error itself carries tag; causes are not checked.
+// Example:
+//
+//	if err.HasTag("external") {
+//	  alertVendor(err)
+//	}
+func (e *Error) HasTag(tag string) bool {
+	if e == nil {
+		return false
+	}
+	for _, t := range e.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// HideFrames hides stack frames whose function name starts with any of
+// prefixes from this error’s Stack and FastStack, in addition to the filtering
+// configured globally, and returns the error. Library authors wrapping this
+// package use it to keep their own layers out of their users’ traces. Frames
+// set via SetStackStrings are not filtered.
+// Example:
+//
+//	return errors.New("query failed").WithStack().HideFrames("github.com/acme/dbkit.")
+func (e *Error) HideFrames(prefixes ...string) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	for _, prefix := range prefixes {
+		if prefix != "" {
+			e.hiddenFrames = append(e.hiddenFrames, prefix)
+		}
+	}
+	return e
+}
+
+// frameFilter returns the global frame filter combined with the prefixes set
+// by HideFrames, or nil if no frame is filtered.
+func (e *Error) frameFilter() func(runtime.Frame) bool {
+	keep := frameFilter()
+	if len(e.hiddenFrames) == 0 {
+		return keep
+	}
+	hidden := e.hiddenFrames
+	return func(frame runtime.Frame) bool {
+		for _, prefix := range hidden {
+			if strings.HasPrefix(frame.Function, prefix) {
+				return false
+			}
+		}
+		return keep == nil || keep(frame)
+	}
+}
+
+// HashKey returns a stable 64-bit FNV-1a hash of the error’s name, code, category,
+// and message (including its cause’s text), for use as a map key when aggregating
+// identical errors. Equal content yields equal keys across instances and processes;
+// context, stack, and count are ignored. Because the message is hashed verbatim,
+// errors differing only in embedded IDs get different keys.
+// Example:
+//
+//	counts[err.HashKey()]++
+func (e *Error) HashKey() uint64 {
+	if e == nil {
+		return 0
+	}
+	h := fnv.New64a()
+	sep := []byte{0}
+	io.WriteString(h, e.name)
+	h.Write(sep)
+	io.WriteString(h, strconv.Itoa(int(e.code)))
+	h.Write(sep)
+	io.WriteString(h, e.category)
+	h.Write(sep)
+	io.WriteString(h, e.msg)
+	if e.cause != nil && !e.formatWrapped {
+		h.Write(sep)
+		io.WriteString(h, e.cause.Error())
+	}
+	return h.Sum64()
+}
+
 // Increment atomically increases the error’s count by 1 and returns the error.
 // Useful for tracking repeated occurrences.
 // Example:
 //
 //	err := err.Increment()
 func (e *Error) Increment() *Error {
+	if e == nil {
+		return nil
+	}
 	atomic.AddUint64(&e.count, 1)
 	return e
 }
 
+// InheritContext copies the context of from, if it is an *Error, into e and
+// returns e. Keys e already has keep their values; only from’s own context is
+// copied, not that of its causes. Useful when re-wrapping or converting errors.
+// Example:
+//
+//	wrapped := errors.New("checkout failed").Wrap(inner).InheritContext(inner)
+func (e *Error) InheritContext(from error) *Error {
+	if e == nil {
+		return nil
+	}
+	src, ok := from.(*Error)
+	if !ok || src == nil || src == e {
+		return e
+	}
+	e = e.mutable()
+	for _, key := range src.ContextKeys() {
+		if _, ok := e.contextValue(key); ok {
+			continue
+		}
+		if v, ok := src.contextValue(key); ok {
+			e.With(key, v)
+		}
+	}
+	return e
+}
+
 // Is checks if the error matches the target by pointer, name, or cause chain.
-// Compatible with errors.Is; also matches by string for standard errors.
+// Compatible with errors.Is; also matches by message for standard errors and
+// for anonymous *Error values (both names empty).
 // Returns true if the error or its cause matches the target.
 // Example:
 //
@@ -803,18 +1403,23 @@ func (e *Error) Is(target error) bool {
 	if e == target {
 		return true
 	}
-	if e.name != "" {
-		if te, ok := target.(*Error); ok && te.name != "" && e.name == te.name {
+	if te, ok := target.(*Error); ok {
+		// Named errors are identified by name only; message equality is a
+		// fallback reserved for anonymous errors on both sides, so two
+		// differently named errors never match just because their text does.
+		if e.name != "" || te.name != "" {
+			if e.name != "" && e.name == te.name {
+				return true
+			}
+		} else if e.Error() == te.Error() {
 			return true
 		}
-	}
-	// String-equality fallback: matches any error whose message equals this
-	// error's message. This is intentional — it allows matching errors created
-	// by fmt.Errorf or errors.New with the same text — but it deviates from
-	// stdlib errors.Is which uses pointer/sentinel identity.
-	// IMPORTANT: two distinct errors with identical messages will match each other.
-	// For strict identity matching use errors.Const() to create named sentinels.
-	if stdErr, ok := target.(error); ok && e.Error() == stdErr.Error() {
+	} else if e.Error() == target.Error() {
+		// String-equality fallback: matches any standard error whose message
+		// equals this error's message. This is intentional — it allows matching
+		// errors created by fmt.Errorf or errors.New with the same text — but it
+		// deviates from stdlib errors.Is which uses pointer/sentinel identity.
+		// For strict identity matching use errors.Const() to create named sentinels.
 		return true
 	}
 	if e.cause != nil {
@@ -837,11 +1442,38 @@ func (e *Error) IsEmpty() bool {
 	return e.msg == "" && e.template == "" && e.name == "" && e.cause == nil
 }
 
-// IsNull checks if the error is nil, empty, or contains only SQL NULL values in its context or cause.
-// Useful for handling database-related errors.
+// IsTransient reports whether the error, or any *Error it wraps, is marked with
+// WithTransient or has the "network" or "system" category. Unlike IsRetryable it
+// never inspects message text, so a message merely mentioning "retry" doesn't count.
 // Example:
 //
-//	if err.IsNull() {
+//	if err.IsTransient() {
+//	  scheduleRetry()
+//	}
+func (e *Error) IsTransient() bool {
+	if e == nil {
+		return false
+	}
+	for current := e; current != nil; {
+		if v, ok := current.contextValue(ctxTransient); ok {
+			if transient, ok := v.(bool); ok && transient {
+				return true
+			}
+		}
+		if current.category == "network" || current.category == "system" {
+			return true
+		}
+		next, _ := current.cause.(*Error)
+		current = next
+	}
+	return false
+}
+
+// IsNull checks if the error is nil, empty, or contains only SQL NULL values in its context or cause.
+// Useful for handling database-related errors.
+// Example:
+//
+//	if err.IsNull() {
 //	  return nil
 //	}
 func (e *Error) IsNull() bool {
@@ -907,6 +1539,9 @@ func (e *Error) IsNull() bool {
 //	data, _ := json.Marshal(err)
 //	fmt.Println(string(data))
 func (e *Error) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
 	// Get buffer from pool. Do NOT defer-return it — we must copy the result
 	// out of buf's backing array and return the buf to the pool BEFORE we return
 	// the copied slice. If we defer the Put, another goroutine can Get the same
@@ -919,66 +1554,174 @@ func (e *Error) MarshalJSON() ([]byte, error) {
 	enc := json.NewEncoder(buf)
 	enc.SetEscapeHTML(false)
 
-	// Prepare JSON structure.
-	je := struct {
-		Name    string                 `json:"name,omitempty"`
-		Message string                 `json:"message,omitempty"`
-		Context map[string]interface{} `json:"context,omitempty"`
-		Cause   interface{}            `json:"cause,omitempty"`
-		Stack   []string               `json:"stack,omitempty"`
-		Code    int                    `json:"code,omitempty"`
-	}{
+	// Encode JSON.
+	if err := enc.Encode(e.jsonView()); err != nil {
+		return nil, err
+	}
+
+	// Copy bytes out of buf before returning buf to the pool.
+	// buf.Bytes() is a slice into buf's internal array — if we put buf back first
+	// and another goroutine resets it, they share the same backing memory.
+	raw := buf.Bytes()
+	if len(raw) > 0 && raw[len(raw)-1] == '\n' {
+		raw = raw[:len(raw)-1]
+	}
+	result := make([]byte, len(raw))
+	copy(result, raw)
+	jsonBufferPool.Put(buf)
+	return result, nil
+}
+
+// errorJSON is the JSON shape shared by MarshalJSON and WriteJSON.
+type errorJSON struct {
+	Name    string                 `json:"name,omitempty"`
+	Op      string                 `json:"op,omitempty"`
+	Message string                 `json:"message,omitempty"`
+	Context map[string]interface{} `json:"context,omitempty"`
+	Tags    []string               `json:"tags,omitempty"`
+	Cause   interface{}            `json:"cause,omitempty"`
+	Stack   []string               `json:"stack,omitempty"`
+	Code    int                    `json:"code,omitempty"`
+}
+
+// jsonView collects the error's fields into the structure used for JSON encoding.
+func (e *Error) jsonView() errorJSON {
+	je := errorJSON{
 		Name:    e.name,
+		Op:      e.op,
 		Message: e.msg,
+		Tags:    e.tags,
 		Code:    e.Code(),
 	}
 
 	// Add context.
 	if ctx := e.Context(); len(ctx) > 0 {
-		je.Context = ctx
+		je.Context = truncateContextValues(ctx, currentConfig.maxJSONLen)
 	}
 
 	// Add stack.
-	if e.stack != nil {
-		je.Stack = e.Stack()
+	if stack := e.Stack(); len(stack) > 0 {
+		je.Stack = stack
 	}
 
 	// Add cause.
 	if e.cause != nil {
-		switch c := e.cause.(type) {
-		case *Error:
-			je.Cause = c
-		case json.Marshaler:
-			je.Cause = c
-		default:
-			je.Cause = c.Error()
+		je.Cause = jsonCause(e.cause)
+	}
+
+	return je
+}
+
+// jsonCause returns the JSON form of a cause: an *Error or json.Marshaler as
+// itself, a *MultiError as an array of its members' forms, and any other error
+// as its message.
+func jsonCause(cause error) interface{} {
+	switch c := cause.(type) {
+	case *Error:
+		return c
+	case *MultiError:
+		errs := c.Errors()
+		members := make([]interface{}, len(errs))
+		for i, err := range errs {
+			if err != nil {
+				members[i] = jsonCause(err)
+			}
 		}
+		return members
+	case json.Marshaler:
+		return c
+	default:
+		return c.Error()
 	}
+}
 
-	// Encode JSON.
-	if err := enc.Encode(je); err != nil {
-		return nil, err
+// WriteJSON encodes the error as JSON directly to w using a pooled encoder,
+// avoiding the intermediate []byte returned by MarshalJSON. The output matches
+// MarshalJSON followed by a newline, as with json.Encoder.
+// Example:
+//
+//	w.Header().Set("Content-Type", "application/json")
+//	_ = err.WriteJSON(w)
+func (e *Error) WriteJSON(w io.Writer) error {
+	if e == nil {
+		_, err := io.WriteString(w, "null\n")
+		return err
 	}
+	s := jsonEncoderPool.Get().(*jsonStreamEncoder)
+	s.w = w
+	err := s.enc.Encode(e.jsonView())
+	s.w = nil // Drop the reference so the pool doesn't pin w.
+	if err == nil {
+		// json.Encoder keeps write errors sticky, so only reuse clean encoders.
+		jsonEncoderPool.Put(s)
+	}
+	return err
+}
 
-	// Copy bytes out of buf before returning buf to the pool.
-	// buf.Bytes() is a slice into buf's internal array — if we put buf back first
-	// and another goroutine resets it, they share the same backing memory.
-	raw := buf.Bytes()
-	if len(raw) > 0 && raw[len(raw)-1] == '\n' {
-		raw = raw[:len(raw)-1]
+// JSON returns the error serialized as compact JSON, or indented with two
+// spaces when indent is true. Unlike json.MarshalIndent, HTML characters are
+// not escaped, matching MarshalJSON.
+// Example:
+//
+//	data, _ := err.JSON(true)
+//	log.Println(string(data))
+func (e *Error) JSON(indent bool) ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
 	}
-	result := make([]byte, len(raw))
-	copy(result, raw)
+	data, err := e.MarshalJSON()
+	if err != nil || !indent {
+		return data, err
+	}
+
+	buf := jsonBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	if err := json.Indent(buf, data, "", "  "); err != nil {
+		jsonBufferPool.Put(buf)
+		return nil, err
+	}
+	// Copy out before returning buf to the pool; see MarshalJSON.
+	result := make([]byte, buf.Len())
+	copy(result, buf.Bytes())
 	jsonBufferPool.Put(buf)
 	return result, nil
 }
 
+// Merge folds other’s metadata into e and returns e; other is left unchanged.
+// Context keys from other are added unless e already has them, other’s code is
+// used only if e has none set, and other’s message is appended with "; " as in Merge.
+// Example:
+//
+//	err := errors.New("save failed").With("user", id).Merge(validationErr)
+func (e *Error) Merge(other *Error) *Error {
+	if e == nil || other == nil || other == e {
+		return e
+	}
+	e = e.mutable()
+	e.InheritContext(other)
+	if !e.hasCode && other.hasCode {
+		e.code, e.hasCode = other.code, true
+	}
+	if msg := other.Error(); msg != "" {
+		if e.msg == "" {
+			e.msg = msg
+		} else {
+			e.msg += "; " + msg
+		}
+	}
+	return e
+}
+
 // Msgf sets the error’s message using a formatted string and returns the error.
 // Overwrites any existing message.
 // Example:
 //
 //	err := err.Msgf("user %s not found", username)
 func (e *Error) Msgf(format string, args ...interface{}) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.msg = fmt.Sprintf(format, args...)
 	return e
 }
@@ -990,30 +1733,133 @@ func (e *Error) Msgf(format string, args ...interface{}) *Error {
 //	  handleAuthError()
 //	}
 func (e *Error) Name() string {
+	if e == nil {
+		return ""
+	}
 	return e.name
 }
 
+// Op returns the operation set by WithOp, or "" if unset.
+// Example:
+//
+//	metrics.Inc("errors_by_op", err.Op())
+func (e *Error) Op() string {
+	if e == nil {
+		return ""
+	}
+	return e.op
+}
+
+// Render returns the error’s template with each {key} placeholder replaced by
+// the context value stored under key, so the message follows the current context.
+// Placeholders naming absent keys, and braces that don't form one, are kept as-is.
+// Printf verbs are not interpreted. Falls back to Error() if no template is set.
+// Example:
+//
+//	err := errors.New("lookup failed").WithTemplate("user {user_id} not found").With("user_id", 42)
+//	err.Render() // "user 42 not found"
+func (e *Error) Render() string {
+	if e == nil {
+		return ""
+	}
+	if e.template == "" {
+		return e.Error()
+	}
+	return e.fillTemplate(e.template)
+}
+
+// fillTemplate replaces each {key} placeholder in tmpl with the context value
+// stored under key, as described for Render.
+func (e *Error) fillTemplate(tmpl string) string {
+	var buf strings.Builder
+	buf.Grow(len(tmpl))
+	for {
+		open := strings.IndexByte(tmpl, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(tmpl[open+1:], '}')
+		if end < 0 {
+			break
+		}
+		end += open + 1
+		buf.WriteString(tmpl[:open])
+		if v, ok := e.contextValue(tmpl[open+1 : end]); ok && end > open+1 {
+			fmt.Fprint(&buf, v)
+		} else {
+			buf.WriteString(tmpl[open : end+1])
+		}
+		tmpl = tmpl[end+1:]
+	}
+	buf.WriteString(tmpl)
+	return buf.String()
+}
+
+// ReplaceCause swaps the wrapped cause for cause and returns the error; nil removes it.
+// Unlike Wrap, it always replaces; if cause exceeds Config.MaxWrapDepth the old
+// cause is still removed. For errors built by Newf with %w, whose message
+// embeds the cause text, that text is swapped too so a sanitized cause doesn't leak.
+// Example:
+//
+//	safe := err.Copy().ReplaceCause(errors.New("internal error"))
+func (e *Error) ReplaceCause(cause error) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	if e.formatWrapped && e.cause != nil && cause != nil {
+		if old := e.cause.Error(); old != "" {
+			if i := strings.LastIndex(e.msg, old); i >= 0 {
+				e.msg = e.msg[:i] + cause.Error() + e.msg[i+len(old):]
+			}
+		}
+	}
+	e.cause = nil
+	if cause != nil {
+		e.setCause(cause)
+	}
+	return e
+}
+
 // Reset clears all fields of the error, preparing it for reuse in the pool.
-// Internal use by Free; does not release stack to stackPool.
+// Every field is zeroed except reusable buffers: the stack keeps its capacity
+// and the context map keeps its buckets. Internal use by Free; does not
+// release stack to stackPool. New fields must be cleared here as well.
+// No-op on a frozen error.
 // Example:
 //
 //	err.Reset() // Clear all fields.
 func (e *Error) Reset() {
+	if e == nil || e.IsFrozen() {
+		return
+	}
 	e.msg = ""
 	e.name = ""
+	e.op = ""
 	e.template = ""
 	e.category = ""
+	e.tags = nil
 	e.code = 0
+	e.hasCode = false
+	e.severity = SeverityNone
+	e.logLevel = 0
+	e.hasLogLevel = false
 	e.count = 0
 	e.cause = nil
 	e.callback = nil
+	e.onError = nil
 	e.formatWrapped = false
+	e.stackStrings = nil
+	e.hiddenFrames = nil
 
 	if e.context != nil {
 		for k := range e.context {
 			delete(e.context, k)
 		}
 	}
+	// Zero every slot, not just the used ones: items migrated to the map
+	// leave their values behind, which would keep them reachable.
+	e.smallContext = [contextSize]contextItem{}
 	e.smallCount = 0
 
 	if e.stack != nil {
@@ -1021,14 +1867,61 @@ func (e *Error) Reset() {
 	}
 }
 
+// LogLevel returns the slog level set by WithLogLevel and whether one was set.
+// Example:
+//
+//	if level, ok := err.LogLevel(); ok {
+//	  logger.Log(ctx, level, "request failed", "err", err)
+//	}
+func (e *Error) LogLevel() (slog.Level, bool) {
+	if e == nil {
+		return 0, false
+	}
+	return e.logLevel, e.hasLogLevel
+}
+
+// Severity returns the error’s severity, or SeverityNone if unset.
+// Example:
+//
+//	if err.Severity() >= errors.SeverityCritical {
+//	  page(err)
+//	}
+func (e *Error) Severity() Severity {
+	if e == nil {
+		return SeverityNone
+	}
+	return e.severity
+}
+
+// Tags returns a copy of the error’s tags in the order they were added, or nil
+// if it has none.
+// Example:
+//
+//	for _, tag := range err.Tags() {
+//	  metrics.Inc("errors_by_tag", tag)
+//	}
+func (e *Error) Tags() []string {
+	if e == nil || len(e.tags) == 0 {
+		return nil
+	}
+	return append([]string(nil), e.tags...)
+}
+
 // Stack returns a detailed stack trace with function names, files, and line numbers.
 // Filters internal frames if configured; returns nil if no stack exists.
+// Frames set via SetStackStrings are returned as-is.
 // Example:
 //
 //	for _, frame := range err.Stack() {
 //	  fmt.Println(frame) // e.g., "main.main main.go:42"
 //	}
 func (e *Error) Stack() []string {
+	if e == nil {
+		return nil
+	}
+	if len(e.stackStrings) > 0 {
+		return append([]string(nil), e.stackStrings...)
+	}
 	// Use len check not nil: a recycled error has stack reset to stack[:0]
 	// (non-nil, zero length). Calling CallersFrames on an empty slice returns
 	// no frames, making Stack() silently return [] instead of nil.
@@ -1036,15 +1929,62 @@ func (e *Error) Stack() []string {
 		return nil
 	}
 
+	return e.decodeStack(-1)
+}
+
+// StackContains reports whether any frame returned by Stack contains substr,
+// so frame filtering applies. Intended for test assertions.
+// Example:
+//
+//	if !err.StackContains("TestCheckout") {
+//	  t.Error("stack should include the test function")
+//	}
+func (e *Error) StackContains(substr string) bool {
+	if e == nil {
+		return false
+	}
+	for _, frame := range e.Stack() {
+		if strings.Contains(frame, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// StackN returns at most the first n frames of the stack trace, in the same
+// format as Stack. Decoding stops once n frames are collected, so it is cheaper
+// than slicing Stack() for deep traces. Returns nil if n <= 0 or no stack exists.
+// Example:
+//
+//	for _, frame := range err.StackN(3) {
+//	  fmt.Println(frame)
+//	}
+func (e *Error) StackN(n int) []string {
+	if e == nil || n <= 0 {
+		return nil
+	}
+	if len(e.stackStrings) > 0 {
+		return append([]string(nil), e.stackStrings[:min(n, len(e.stackStrings))]...)
+	}
+	if len(e.stack) == 0 {
+		return nil
+	}
+	return e.decodeStack(n)
+}
+
+// decodeStack resolves the captured program counters into "function file:line"
+// strings, dropping frames rejected by frameFilter; limit < 0 decodes every frame.
+func (e *Error) decodeStack(limit int) []string {
+	keep := e.frameFilter()
 	frames := runtime.CallersFrames(e.stack)
 	var trace []string
-	for {
+	for limit < 0 || len(trace) < limit {
 		frame, more := frames.Next()
 		if frame == (runtime.Frame{}) {
 			break
 		}
 
-		if currentConfig.filterInternal && isInternalFrame(frame) {
+		if keep != nil && !keep(frame) {
 			continue
 		}
 
@@ -1060,14 +2000,58 @@ func (e *Error) Stack() []string {
 	return trace
 }
 
+// SetStackStrings attaches pre-rendered stack frames, such as those returned by
+// Stack() on a remote or logged error, and returns the error. Stack and
+// FastStack return these frames verbatim in place of any captured trace.
+// Passing an empty slice removes previously set frames.
+// Example:
+//
+//	err := errors.New(remote.Message).SetStackStrings(remote.Stack)
+func (e *Error) SetStackStrings(frames []string) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	if len(frames) == 0 {
+		e.stackStrings = nil
+		return e
+	}
+	e.stackStrings = append([]string(nil), frames...)
+	return e
+}
+
+// StripStack drops the error’s stack trace in place, returning the buffer to
+// the stack pool, and returns the error. Frames set via SetStackStrings are
+// removed as well. Use it before caching an error long-term; a later Free
+// does not return the buffer twice.
+// Example:
+//
+//	cache.Set(key, err.StripStack())
+func (e *Error) StripStack() *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	e.stackStrings = nil
+	if e.stack != nil {
+		stackPool.Put(e.stack[:cap(e.stack)])
+		e.stack = nil
+	}
+	return e
+}
+
 // Trace ensures the error has a stack trace, capturing it if absent.
 // Returns the error for chaining.
 // Example:
 //
 //	err := errors.New("failed").Trace()
 func (e *Error) Trace() *Error {
+	if e == nil {
+		return nil
+	}
 	// Check len rather than nil for the same reason as WithStack.
 	if len(e.stack) == 0 {
+		e = e.mutable()
 		// skip=1: trimmed = skip+1 = 2, removes captureStack + Trace() itself.
 		e.stack = captureStack(1)
 	}
@@ -1094,6 +2078,9 @@ func (e *Error) Transform(fn func(*Error)) *Error {
 //
 //	cause := errors.Unwrap(err)
 func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
 	return e.cause
 }
 
@@ -1148,15 +2135,161 @@ func (e *Error) Walk(fn func(error)) {
 	if e == nil || fn == nil {
 		return
 	}
-	current := error(e)
-	for current != nil {
-		fn(current)
-		if unwrappable, ok := current.(interface{ Unwrap() error }); ok {
-			current = unwrappable.Unwrap()
-		} else {
+	e.WalkUntil(func(err error) bool {
+		fn(err)
+		return true
+	})
+}
+
+// WalkUntil traverses the error chain from e, applying fn to each error until
+// fn returns false or the chain ends, and returns e for further chaining. A
+// chain that loops back to an *Error already visited stops there.
+// Example:
+//
+//	msg := err.WalkUntil(func(e error) bool {
+//	  log.Println(e)
+//	  return errors.Code(e) != 404 // Stop at the first 404
+//	}).Format()
+func (e *Error) WalkUntil(fn func(error) bool) *Error {
+	if e == nil || fn == nil {
+		return e
+	}
+	var seen map[*Error]struct{}
+	for current := error(e); current != nil; current = errors.Unwrap(current) {
+		if ce, ok := current.(*Error); ok && ce.cause != nil {
+			// Only *Error causes can be rewired into a loop; track them lazily.
+			if seen == nil {
+				seen = make(map[*Error]struct{})
+			}
+			if _, dup := seen[ce]; dup {
+				break
+			}
+			seen[ce] = struct{}{}
+		}
+		if !fn(current) {
 			break
 		}
 	}
+	return e
+}
+
+// Without returns a copy of the error with the named fields cleared, useful for
+// normalizing errors before comparison or caching. Recognized fields are
+// "stack", "count", "code", "category", "tags", and "context" (all context); any
+// other name is treated as a context key to remove. The original is unchanged.
+// Example:
+//
+//	a := err1.Without("stack", "count", "trace_id")
+//	b := err2.Without("stack", "count", "trace_id")
+func (e *Error) Without(fields ...string) *Error {
+	if e == nil {
+		return nil
+	}
+	newErr := e.Copy()
+	newErr.mu.Lock()
+	defer newErr.mu.Unlock()
+	for _, field := range fields {
+		switch field {
+		case "stack":
+			newErr.stack = newErr.stack[:0]
+			newErr.stackStrings = nil
+		case "count":
+			newErr.count = 0
+		case "code":
+			newErr.code = 0
+			newErr.hasCode = false
+		case "category":
+			newErr.category = ""
+		case "tags":
+			newErr.tags = nil
+		case "context":
+			for i := int32(0); i < newErr.smallCount; i++ {
+				newErr.smallContext[i] = contextItem{}
+			}
+			newErr.smallCount = 0
+			newErr.context = nil
+		default:
+			newErr.removeContextKey(field)
+		}
+	}
+	return newErr
+}
+
+// FilterContext returns a copy of the error whose context holds only the
+// listed keys, e.g. to log fewer fields at lower verbosity. Keys not present
+// are ignored. The original is unchanged.
+// Example:
+//
+//	logger.Info(err.FilterContext("request_id", "user").Error())
+func (e *Error) FilterContext(keep ...string) *Error {
+	if e == nil {
+		return nil
+	}
+	newErr := e.Copy()
+	drop := newErr.ContextKeys()
+	newErr.mu.Lock()
+	defer newErr.mu.Unlock()
+	for _, key := range drop {
+		kept := false
+		for _, k := range keep {
+			if k == key {
+				kept = true
+				break
+			}
+		}
+		if !kept {
+			newErr.removeContextKey(key)
+		}
+	}
+	return newErr
+}
+
+// Scrub returns a copy of the error with every match of patterns in its
+// message, context values, and causes replaced by "[REDACTED]". Context values
+// are matched as text and replaced by a string only if something matched.
+// Uses DefaultScrubPatterns if no patterns are given. A cause that is not an
+// *Error is replaced by a plain error if its message changes, so it no longer
+// matches errors.Is. Use it before reporting an error outside the service.
+// Example:
+//
+//	report(err.Scrub())
+func (e *Error) Scrub(patterns ...*regexp.Regexp) *Error {
+	if e == nil {
+		return nil
+	}
+	if len(patterns) == 0 {
+		patterns = DefaultScrubPatterns
+	}
+	return e.scrub(patterns, make(map[*Error]bool))
+}
+
+// scrub implements Scrub, tracking visited errors to stop on cyclic chains.
+func (e *Error) scrub(patterns []*regexp.Regexp, seen map[*Error]bool) *Error {
+	seen[e] = true
+	newErr := e.Copy()
+	newErr.msg = scrubString(newErr.msg, patterns)
+
+	newErr.mu.Lock()
+	for i := int32(0); i < newErr.smallCount; i++ {
+		newErr.smallContext[i].value = scrubValue(newErr.smallContext[i].value, patterns)
+	}
+	for k, v := range newErr.context {
+		newErr.context[k] = scrubValue(v, patterns)
+	}
+	newErr.mu.Unlock()
+
+	switch cause := newErr.cause.(type) {
+	case nil:
+	case *Error:
+		if cause != nil && !seen[cause] {
+			newErr.cause = cause.scrub(patterns, seen)
+		}
+	default:
+		if msg := cause.Error(); scrubString(msg, patterns) != msg {
+			newErr.cause = errors.New(scrubString(msg, patterns))
+		}
+	}
+	return newErr
 }
 
 // With adds key-value pairs to the error's context and returns the error.
@@ -1166,9 +2299,10 @@ func (e *Error) Walk(fn func(error)) {
 //
 //	err := err.With("key1", value1, "key2", value2)
 func (e *Error) With(keyValues ...interface{}) *Error {
-	if len(keyValues) == 0 {
+	if e == nil || len(keyValues) == 0 {
 		return e
 	}
+	e = e.mutable()
 
 	// Validate that we have an even number of arguments
 	if len(keyValues)%2 != 0 {
@@ -1231,11 +2365,36 @@ func max(a, b int) int {
 	return b
 }
 
+// WithCaller records the immediate caller as "file:line:function" in the context
+// under the "caller" key and returns the error. Far cheaper than WithStack when
+// only the creation site is needed; the key appears in Format() and JSON output.
+// Example:
+//
+//	err := errors.New("failed").WithCaller()
+func (e *Error) WithCaller() *Error {
+	if e == nil {
+		return nil
+	}
+	pc, file, line, ok := runtime.Caller(1)
+	if !ok {
+		return e
+	}
+	function := "unknown"
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		function = fn.Name()
+	}
+	return e.With(ctxCaller, fmt.Sprintf("%s:%d:%s", file, line, function))
+}
+
 // WithCategory sets the error’s category and returns the error.
 // Example:
 //
 //	err := err.WithCategory("validation")
 func (e *Error) WithCategory(category ErrorCategory) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.category = string(category)
 	return e
 }
@@ -1245,7 +2404,40 @@ func (e *Error) WithCategory(category ErrorCategory) *Error {
 //
 //	err := err.WithCode(400)
 func (e *Error) WithCode(code int) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.code = int32(code)
+	e.hasCode = true
+	return e
+}
+
+// WithExitCode stores a process exit code under the reserved "exit_code"
+// context key and returns the error. Unlike the HTTP-style code set by WithCode,
+// it is meant for os.Exit and should be in the range 0–255.
+// Example:
+//
+//	err := errors.New("config not found").WithExitCode(78)
+func (e *Error) WithExitCode(code int) *Error {
+	if e == nil {
+		return nil
+	}
+	return e.With(ctxExitCode, code)
+}
+
+// WithLogLevel sets the slog level the error should be logged at and returns
+// the error. Chain logging uses it instead of slog.LevelError.
+// Example:
+//
+//	err := errors.New("cache miss").WithLogLevel(slog.LevelWarn)
+func (e *Error) WithLogLevel(level slog.Level) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	e.logLevel = level
+	e.hasLogLevel = true
 	return e
 }
 
@@ -1254,38 +2446,218 @@ func (e *Error) WithCode(code int) *Error {
 //
 //	err := err.WithName("AuthError")
 func (e *Error) WithName(name string) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.name = name
 	return e
 }
 
+// WithOp sets the operation that failed, such as "users.Create", and returns
+// the error. Error() prefixes it, so wrapping errors that each name their
+// operation builds a readable trace: "users.Create: db.Insert: connection refused".
+// Use Ops to collect the operations along a chain.
+// Example:
+//
+//	return errors.Empty().WithOp("users.Create").Wrap(err)
+func (e *Error) WithOp(op string) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	e.op = op
+	return e
+}
+
+// WithRequestID stores a request correlation ID under the reserved
+// "request_id" context key and returns the error.
+// Use RequestID to extract it from anywhere in the chain.
+// Example:
+//
+//	err := err.WithRequestID(r.Header.Get("X-Request-ID"))
+func (e *Error) WithRequestID(id string) *Error {
+	if e == nil {
+		return nil
+	}
+	return e.With(ctxRequestID, id)
+}
+
 // WithRetryable marks the error as retryable in its context and returns the error.
 // Example:
 //
 //	err := err.WithRetryable()
 func (e *Error) WithRetryable() *Error {
+	if e == nil {
+		return nil
+	}
 	return e.With(ctxRetry, true)
 }
 
+// WithSeverity sets the error’s severity and returns the error.
+// Example:
+//
+//	err := errors.New("disk full").WithSeverity(errors.SeverityFatal)
+func (e *Error) WithSeverity(severity Severity) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	e.severity = severity
+	return e
+}
+
 // WithStack captures a stack trace if none exists and returns the error.
 // Skips one frame (caller of WithStack).
 // Example:
 //
 //	err := errors.New("failed").WithStack()
 func (e *Error) WithStack() *Error {
+	if e == nil {
+		return nil
+	}
 	// Check len rather than nil: a pooled error has stack reset to stack[:0]
 	// (non-nil but empty). The nil check would skip capture for recycled errors.
 	if len(e.stack) == 0 {
+		e = e.mutable()
 		e.stack = captureStack(1)
 	}
 	return e
 }
 
+// WithCallSite records a one-frame stack, the program counter of its call site,
+// if the error has no stack, and returns the error. It is not a deferred full
+// trace: a caller's frames are gone once it returns, so only WithStack, which
+// walks them at creation, can report them. It is much cheaper than WithStack
+// for hot paths that trace defensively but rarely read the stack; Stack()
+// resolves the frame on first use, as it does for every stack.
+// Example:
+//
+//	err := errors.New("cache miss").WithCallSite()
+func (e *Error) WithCallSite() *Error {
+	if e == nil {
+		return nil
+	}
+	if len(e.stack) == 0 {
+		e = e.mutable()
+		var pc [1]uintptr
+		// Skip runtime.Callers and WithCallSite itself.
+		if runtime.Callers(2, pc[:]) == 1 {
+			e.setStack(pc[:])
+		}
+	}
+	return e
+}
+
+// WithStackIf captures a stack trace like WithStack, but only when cond is true.
+// Returns the error either way, avoiding a branch at each call site.
+// Example:
+//
+//	err := errors.New("failed").WithStackIf(debug)
+func (e *Error) WithStackIf(cond bool) *Error {
+	if e == nil {
+		return nil
+	}
+	// Capture here rather than calling WithStack so the skip count is unchanged.
+	if cond && len(e.stack) == 0 {
+		e = e.mutable()
+		e.stack = captureStack(1)
+	}
+	return e
+}
+
+// WithStackFrom adopts the stack of err, or of the innermost error in its chain
+// that carries one, replacing e’s stack; use it when converting a third-party
+// error so the trace points at its origin rather than the conversion site.
+// Recognized are *Error, pkg/errors-style StackTrace() methods returning a slice
+// of program counters, and Callers() []uintptr (go-errors). If no stack is found,
+// e is returned unchanged.
+// Example:
+//
+//	err := errors.New("upstream failed").Wrap(pkgErr).WithStackFrom(pkgErr)
+func (e *Error) WithStackFrom(err error) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	pcs, frames := stackFrom(err)
+	switch {
+	case len(frames) > 0:
+		e.stackStrings = frames
+		if e.stack != nil {
+			e.stack = e.stack[:0]
+		}
+	case len(pcs) > 0:
+		e.setStack(pcs)
+	}
+	return e
+}
+
+// WithStackTrace sets the error’s stack from program counters captured by the
+// caller, e.g. with runtime.Callers, and returns the error. Use it to capture a
+// stack once and apply it to several errors. pcs is copied into a pooled
+// buffer, so the caller may keep reusing it; an empty pcs removes the stack.
+// Example:
+//
+//	pcs := make([]uintptr, 32)
+//	pcs = pcs[:runtime.Callers(2, pcs)]
+//	errA := errors.New("a").WithStackTrace(pcs)
+//	errB := errors.New("b").WithStackTrace(pcs)
+func (e *Error) WithStackTrace(pcs []uintptr) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	if len(pcs) == 0 {
+		e.stackStrings = nil
+		if e.stack != nil {
+			e.stack = e.stack[:0]
+		}
+		return e
+	}
+	e.setStack(pcs)
+	return e
+}
+
+// setStack copies pcs into e.stack, replacing any set stack strings.
+func (e *Error) setStack(pcs []uintptr) {
+	e.stackStrings = nil
+	if e.stack == nil {
+		// Use a pooled buffer, as Free returns e.stack to stackPool.
+		e.stack = stackPool.Get().([]uintptr)
+	}
+	e.stack = append(e.stack[:0], pcs...)
+}
+
+// WithTags adds labels to the error for filtering and returns the error. Unlike
+// the single category, an error can carry any number of tags; empty and
+// already present tags are ignored. Tags are included in JSON output.
+// Example:
+//
+//	err := errors.New("charge failed").WithTags("payment", "external", "stripe")
+func (e *Error) WithTags(tags ...string) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	for _, tag := range tags {
+		if tag != "" && !e.HasTag(tag) {
+			e.tags = append(e.tags, tag)
+		}
+	}
+	return e
+}
+
 // WithTemplate sets a message template and returns the error.
 // Used as a fallback if the message is empty.
 // Example:
 //
 //	err := err.WithTemplate("operation failed")
 func (e *Error) WithTemplate(template string) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.template = template
 	return e
 }
@@ -1295,42 +2667,109 @@ func (e *Error) WithTemplate(template string) *Error {
 //
 //	err := err.WithTimeout()
 func (e *Error) WithTimeout() *Error {
+	if e == nil {
+		return nil
+	}
 	return e.With(ctxTimeout, true)
 }
 
+// WithTransient marks the error as transient in its context and returns the error.
+// See IsTransient.
+// Example:
+//
+//	err := errors.New("connection reset").WithTransient()
+func (e *Error) WithTransient() *Error {
+	if e == nil {
+		return nil
+	}
+	return e.With(ctxTransient, true)
+}
+
 // Wrap associates a cause error with this error, creating a chain.
-// Returns the error unchanged if cause is nil.
+// Returns the error unchanged if cause is nil. With Config.MaxWrapDepth set,
+// a cause whose chain is already that deep is dropped along with everything
+// it wraps, so the root cause is lost too.
 // Example:
 //
 //	err := errors.New("failed").Wrap(errors.New("cause"))
 func (e *Error) Wrap(cause error) *Error {
-	if cause == nil {
+	if e == nil || cause == nil {
 		return e
 	}
+	e = e.mutable()
+	e.setCause(cause)
+	return e
+}
+
+// setCause sets e’s cause unless that would make the chain deeper than
+// Config.MaxWrapDepth, in which case the cause is dropped.
+func (e *Error) setCause(cause error) {
+	if limit := currentConfig.maxWrapDepth; limit > 0 && chainDepth(cause, limit) >= limit {
+		return
+	}
 	e.cause = cause
+}
+
+// WrapMany sets several independent causes, e.g. a failed rollback alongside the
+// original failure, and returns the error. Nil causes are dropped; with one left it
+// behaves like Wrap, and with none the error is unchanged. Since *Error already has
+// Unwrap() error, the causes are held in a *MultiError whose Unwrap() []error
+// lists them, so errors.Is and errors.As check each one. Unlike Join, causes
+// with the same message are all kept. Config.MaxWrapDepth applies as for Wrap.
+// Example:
+//
+//	err := errors.New("transfer failed").WrapMany(debitErr, rollbackErr)
+func (e *Error) WrapMany(causes ...error) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	nonNil := make([]error, 0, len(causes))
+	for _, cause := range causes {
+		if cause != nil {
+			nonNil = append(nonNil, cause)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+	case 1:
+		e.setCause(nonNil[0])
+	default:
+		// Built directly rather than with Add, which drops repeated messages.
+		e.setCause(&MultiError{errors: nonNil})
+	}
 	return e
 }
 
 // Wrapf wraps a cause error with formatted message and returns the error.
-// If cause is nil, returns the error unchanged.
+// If cause is nil, only the message is set. Config.MaxWrapDepth applies as for Wrap.
 // Example:
 //
 //	err := errors.New("base").Wrapf(io.EOF, "read failed: %s", "file.txt")
 func (e *Error) Wrapf(cause error, format string, args ...interface{}) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.msg = fmt.Sprintf(format, args...)
 	if cause != nil {
-		e.cause = cause
+		e.setCause(cause)
 	}
 	return e
 }
 
 // WrapNotNil wraps a cause error only if it is non-nil and returns the error.
+// Config.MaxWrapDepth applies as for Wrap.
 // Example:
 //
 //	err := err.WrapNotNil(maybeError)
 func (e *Error) WrapNotNil(cause error) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	if cause != nil {
-		e.cause = cause
+		e.setCause(cause)
 	}
 	return e
 }
@@ -66,6 +66,17 @@ func BenchmarkStack_WithStack(b *testing.B) {
 	}
 }
 
+// BenchmarkStack_WithCallSite measures recording only the call site. It does
+// less work than BenchmarkStack_WithStack, one frame against the full trace, so
+// the gap shows what dropping the rest of the trace saves, not a faster capture.
+func BenchmarkStack_WithCallSite(b *testing.B) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := New("test").WithCallSite() // Record the call site only
+		err.Free()
+	}
+}
+
 // BenchmarkStack_Trace measures creating an error with a stack trace.
 func BenchmarkStack_Trace(b *testing.B) {
 	b.ResetTimer()
@@ -110,6 +121,30 @@ func BenchmarkContext_Small(b *testing.B) {
 	err.Free()
 }
 
+// BenchmarkContext_SingleKey measures the common single-pair With on a fresh error.
+// A separate lock-free single-key slot was considered and not adopted: the pair
+// already lands in smallContext without allocating, and skipping the lock would
+// make With unsafe for concurrent callers.
+func BenchmarkContext_SingleKey(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := New("invalid input").With("field", "email") // One key, stored inline
+		err.Free()
+	}
+}
+
+// BenchmarkContext_SingleKeyRead measures reading a single-key context back.
+func BenchmarkContext_SingleKeyRead(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := New("invalid input").With("field", "email")
+		_ = err.Context() // Materializes the map, reused across pooled instances
+		err.Free()
+	}
+}
+
 // BenchmarkContext_Map measures adding context exceeding smallContext capacity.
 func BenchmarkContext_Map(b *testing.B) {
 	err := New("base")
@@ -241,6 +276,32 @@ func BenchmarkContext_Concurrent(b *testing.B) {
 	})
 }
 
+// BenchmarkConcurrency_MultiErrorAdd compares concurrent MultiError.Add with a single
+// mutex against striped accumulation. Count is called periodically to merge stripes.
+func BenchmarkConcurrency_MultiErrorAdd(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		opts []MultiErrorOption
+	}{
+		{"Mutex", nil},
+		{"Striped", []MultiErrorOption{WithStriped(16)}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			m := NewMultiError(bc.opts...)
+			err := New("concurrent")
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					m.Add(err)
+					if i++; i%1024 == 0 {
+						_ = m.Count()
+					}
+				}
+			})
+		})
+	}
+}
+
 // Pool and Allocation Benchmarks
 // These benchmarks evaluate pooling mechanisms and raw allocation costs.
 
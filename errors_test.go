@@ -6,14 +6,20 @@
 package errors
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -166,6 +172,32 @@ func TestErrorIs(t *testing.T) {
 	}
 }
 
+// TestErrorIsMessageMatching verifies that anonymous errors match by message
+// through the chain, while named errors are never matched by message alone.
+func TestErrorIsMessageMatching(t *testing.T) {
+	inner := New("connection timeout")
+	outer := New("request failed").Wrap(inner)
+
+	if !errors.Is(outer, New("connection timeout")) {
+		t.Error("Is() should match anonymous *Error by message through the chain")
+	}
+	if !errors.Is(outer, errors.New("connection timeout")) {
+		t.Error("Is() should match standard error by message through the chain")
+	}
+	if errors.Is(outer, New("something else")) {
+		t.Error("Is() should not match a different message")
+	}
+
+	named := Named("TimeoutError").Msgf("connection timeout")
+	defer named.Free()
+	if named.Is(New("connection timeout")) {
+		t.Error("Is() should not match a named error against an anonymous one by message")
+	}
+	if inner.Is(Named("OtherError").Msgf("connection timeout")) {
+		t.Error("Is() should not match an anonymous error against a named one by message")
+	}
+}
+
 // TestErrorAs checks that As unwraps to the correct error type, supporting
 // both custom *Error and standard library errors.
 func TestErrorAs(t *testing.T) {
@@ -179,265 +211,1835 @@ func TestErrorAs(t *testing.T) {
 		t.Errorf("As() unwrapped to wrong error, got %v, want %v", target.name, "target")
 	}
 
-	stdErr := errors.New("std error")
-	err = New("wrapper").Wrap(stdErr)
-	defer err.Free()
-	var stdTarget error
-	if !As(err, &stdTarget) {
-		t.Errorf("As() failed, should unwrap to stdlib error")
+	stdErr := errors.New("std error")
+	err = New("wrapper").Wrap(stdErr)
+	defer err.Free()
+	var stdTarget error
+	if !As(err, &stdTarget) {
+		t.Errorf("As() failed, should unwrap to stdlib error")
+	}
+	if stdTarget != stdErr {
+		t.Errorf("As() unwrapped to wrong error, got %v, want %v", stdTarget, stdErr)
+	}
+}
+
+// TestErrorCount verifies that Count tracks per-instance error occurrences.
+func TestErrorCount(t *testing.T) {
+	err := New("unnamed")
+	defer err.Free()
+	if err.Count() != 0 {
+		t.Errorf("Count() on new error should be 0, got %d", err.Count())
+	}
+
+	err = Named("test_count").Increment()
+	if err.Count() != 1 {
+		t.Errorf("Count() after Increment() should be 1, got %d", err.Count())
+	}
+}
+
+// TestErrorCode ensures that Code correctly sets and retrieves HTTP status codes.
+func TestErrorCode(t *testing.T) {
+	err := New("unnamed")
+	defer err.Free()
+	if err.Code() != 0 {
+		t.Errorf("Code() on new error should be 0, got %d", err.Code())
+	}
+
+	err = Named("test_code").WithCode(400)
+	if err.Code() != 400 {
+		t.Errorf("Code() after WithCode(400) should be 400, got %d", err.Code())
+	}
+}
+
+// TestErrorMarshalJSON verifies that JSON serialization includes all expected
+// fields: message, context, cause, code, and stack (when present).
+func TestErrorMarshalJSON(t *testing.T) {
+	// Test basic error with context, code, and cause.
+	err := New("test").
+		With("key", "value").
+		WithCode(400).
+		Wrap(Named("cause"))
+	defer err.Free()
+	data, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("MarshalJSON() failed: %v", e)
+	}
+
+	want := map[string]interface{}{
+		"message": "test",
+		"context": map[string]interface{}{"key": "value"},
+		"cause":   map[string]interface{}{"name": "cause"},
+		"code":    float64(400),
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if got["message"] != want["message"] {
+		t.Errorf("MarshalJSON() message = %v, want %v", got["message"], want["message"])
+	}
+	if !reflect.DeepEqual(got["context"], want["context"]) {
+		t.Errorf("MarshalJSON() context = %v, want %v", got["context"], want["context"])
+	}
+	if cause, ok := got["cause"].(map[string]interface{}); !ok || cause["name"] != "cause" {
+		t.Errorf("MarshalJSON() cause = %v, want %v", got["cause"], want["cause"])
+	}
+	if code, ok := got["code"].(float64); !ok || code != 400 {
+		t.Errorf("MarshalJSON() code = %v, want %v", got["code"], 400)
+	}
+
+	// Test error with stack trace.
+	t.Run("WithStack", func(t *testing.T) {
+		err := New("test").WithStack().WithCode(500)
+		defer err.Free()
+		data, e := json.Marshal(err)
+		if e != nil {
+			t.Fatalf("MarshalJSON() failed: %v", e)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if _, ok := got["stack"].([]interface{}); !ok || len(got["stack"].([]interface{})) == 0 {
+			t.Error("MarshalJSON() should include non-empty stack")
+		}
+		if code, ok := got["code"].(float64); !ok || code != 500 {
+			t.Errorf("MarshalJSON() code = %v, want 500", got["code"])
+		}
+	})
+}
+
+// TestErrorJSON verifies that JSON produces compact output matching MarshalJSON
+// and indented output matching json.MarshalIndent, without escaping HTML.
+func TestErrorJSON(t *testing.T) {
+	err := New("test").With("key", "value").WithCode(400).Wrap(Named("cause"))
+	defer err.Free()
+
+	compact, e := err.JSON(false)
+	if e != nil {
+		t.Fatalf("JSON(false) failed: %v", e)
+	}
+	marshaled, _ := err.MarshalJSON()
+	if string(compact) != string(marshaled) {
+		t.Errorf("JSON(false) = %s, want %s", compact, marshaled)
+	}
+
+	indented, e := err.JSON(true)
+	if e != nil {
+		t.Fatalf("JSON(true) failed: %v", e)
+	}
+	want, _ := json.MarshalIndent(err, "", "  ")
+	if string(indented) != string(want) {
+		t.Errorf("JSON(true) =\n%s\nwant\n%s", indented, want)
+	}
+
+	html := New("a < b && c > d")
+	defer html.Free()
+	data, _ := html.JSON(true)
+	if !strings.Contains(string(data), "a < b && c > d") {
+		t.Errorf("JSON(true) should not escape HTML, got %s", data)
+	}
+}
+
+// TestErrorResetClearsAllFields populates every field, returns the error to
+// the pool, and asserts that nothing but reusable buffers survives. Uses
+// reflection so newly added fields are covered automatically.
+func TestErrorResetClearsAllFields(t *testing.T) {
+	err := Newf("wrapped: %w", New("cause")).
+		WithName("ResetError").
+		WithTemplate("template").
+		WithCategory("category").
+		WithCode(418).
+		WithSeverity(SeverityError).
+		WithTags("tag").
+		WithLogLevel(slog.LevelWarn).
+		WithOp("op").
+		HideFrames("testing.").
+		WithStack().
+		Callback(func() {}).
+		OnError(func(*Error) {}).
+		Increment().
+		With("a", 1, "b", 2)
+	_ = err.Context() // Materialize the map alongside smallContext
+	err.With("c", 3, "d", 4, "e", 5)
+	_ = err.Error()
+
+	errorPool.Put(err)
+
+	// Buffers kept for reuse; everything else must be zero.
+	reusable := map[string]bool{"stack": true, "context": true, "mu": true}
+	v := reflect.ValueOf(err).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		if reusable[name] {
+			continue
+		}
+		if !v.Field(i).IsZero() {
+			t.Errorf("Reset() left field %q non-zero", name)
+		}
+	}
+	if len(err.stack) != 0 {
+		t.Errorf("Reset() left %d stack frames", len(err.stack))
+	}
+	if len(err.context) != 0 {
+		t.Errorf("Reset() left context %v", err.context)
+	}
+}
+
+// TestErrorEdgeCases verifies behavior for unusual inputs, such as nil errors,
+// empty names, and standard library error wrapping.
+func TestErrorEdgeCases(t *testing.T) {
+	// Test nil error handling.
+	var nilErr *Error
+	if nilErr.Is(nil) {
+		t.Errorf("nil.Is(nil) should be false, got true")
+	}
+	if Is(nilErr, New("test")) {
+		t.Errorf("Is(nil, non-nil) should be false")
+	}
+
+	// Test empty name mismatch.
+	err := New("empty name")
+	defer err.Free()
+	if err.Is(Named("")) {
+		t.Errorf("Error with empty name should not match unnamed error")
+	}
+
+	// Test wrapping standard library error.
+	stdErr := errors.New("std error")
+	customErr := New("custom").Wrap(stdErr)
+	defer customErr.Free()
+	if !Is(customErr, stdErr) {
+		t.Errorf("Is() should match stdlib error through wrapping")
+	}
+
+	// Test As with nil error.
+	var nilTarget *Error
+	if As(nilErr, &nilTarget) {
+		t.Errorf("As(nil, &nilTarget) should return false")
+	}
+
+	// Additional edge case: Wrapping nil error.
+	t.Run("WrapNil", func(t *testing.T) {
+		err := New("wrapper").Wrap(nil)
+		defer err.Free()
+		if err.Unwrap() != nil {
+			t.Errorf("Wrap(nil) should set cause to nil, got %v", err.Unwrap())
+		}
+		if err.Error() != "wrapper" {
+			t.Errorf("Wrap(nil) should preserve message, got %v, want %v", err.Error(), "wrapper")
+		}
+	})
+}
+
+// TestErrorRetryWithCallback verifies the retry mechanism, ensuring the callback
+// is invoked correctly and retries exhaust as expected for retryable errors.
+func TestErrorRetryWithCallback(t *testing.T) {
+	// Test retry with multiple attempts.
+	attempts := 0
+	retry := NewRetry(
+		WithMaxAttempts(3),
+		WithDelay(1*time.Millisecond),
+		WithOnRetry(func(attempt int, err error) {
+			attempts++
+		}),
+	)
+
+	err := retry.Execute(func() error {
+		return New("retry me").WithRetryable()
+	})
+
+	if attempts != 3 {
+		t.Errorf("Expected 3 retry attempts, got %d", attempts)
+	}
+	if err == nil {
+		t.Error("Expected retry to exhaust with error, got nil")
+	}
+
+	// Test zero max attempts, expecting one initial attempt (not a retry).
+	t.Run("ZeroAttempts", func(t *testing.T) {
+		attempts := 0
+		retry := NewRetry(
+			WithMaxAttempts(0),
+			WithOnRetry(func(attempt int, err error) {
+				attempts++
+			}),
+		)
+		err := retry.Execute(func() error {
+			return New("retry me").WithRetryable()
+		})
+		// Expect one attempt, as Execute runs the function once before checking retries.
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt (initial execution), got %d", attempts)
+		}
+		if err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+// TestErrorOnError verifies that OnError receives the error's fields when
+// Error() is called, and that reading the message from the hook does not recurse.
+func TestErrorOnError(t *testing.T) {
+	var gotMsg string
+	var gotCode int
+	calls := 0
+	err := New("hook error").WithCode(503).OnError(func(e *Error) {
+		calls++
+		gotMsg = e.Error()
+		gotCode = e.Code()
+	})
+	defer err.Free()
+
+	if msg := err.Error(); msg != "hook error" {
+		t.Errorf("Error() = %q, want %q", msg, "hook error")
+	}
+	if calls != 1 {
+		t.Errorf("OnError callback called %d times, want 1", calls)
+	}
+	if gotMsg != "hook error" || gotCode != 503 {
+		t.Errorf("OnError received msg=%q code=%d, want %q and 503", gotMsg, gotCode, "hook error")
+	}
+
+	copied := err.Copy()
+	defer copied.Free()
+	_ = copied.Error()
+	if calls != 2 {
+		t.Errorf("Copy() should preserve OnError, got %d calls", calls)
+	}
+}
+
+// TestErrorOnErrorWithCallback verifies that Callback fires once per Error()
+// call when OnError is also set, even if the hook reads the message itself.
+func TestErrorOnErrorWithCallback(t *testing.T) {
+	callbacks, hooks := 0, 0
+	err := New("hook error").
+		Callback(func() { callbacks++ }).
+		OnError(func(e *Error) {
+			hooks++
+			_ = e.Error()
+		})
+	defer err.Free()
+
+	for i := 1; i <= 3; i++ {
+		_ = err.Error()
+		if callbacks != i || hooks != i {
+			t.Fatalf("after %d Error() calls: Callback fired %d times, OnError %d, want %d each", i, callbacks, hooks, i)
+		}
+	}
+}
+
+// TestErrorOnErrorConcurrent verifies that OnError fires for every Error() call
+// when several goroutines read the same error at once.
+func TestErrorOnErrorConcurrent(t *testing.T) {
+	const readers = 8
+	var calls int64
+	release := make(chan struct{})
+	err := New("hook error").OnError(func(e *Error) {
+		// Hold every hook open until all readers are inside it at once.
+		if atomic.AddInt64(&calls, 1) == readers {
+			close(release)
+		}
+		select {
+		case <-release:
+		case <-time.After(time.Second):
+		}
+		_ = e.Error()
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = err.Error()
+		}()
+	}
+	wg.Wait()
+	if got := atomic.LoadInt64(&calls); got != readers {
+		t.Errorf("OnError called %d times for %d concurrent reads, want %d", got, readers, readers)
+	}
+}
+
+// TestErrorStackPresence confirms stack trace behavior for New and Trace methods.
+func TestErrorStackPresence(t *testing.T) {
+	// New should not capture stack.
+	err := New("test")
+	if len(err.Stack()) != 0 {
+		t.Error("New() should not capture stack")
+	}
+
+	// Trace should capture stack.
+	traced := Trace("test")
+	if len(traced.Stack()) == 0 {
+		t.Error("Trace() should capture stack")
+	}
+}
+
+// TestErrorStackDepth ensures that stack traces respect the configured maximum depth.
+func TestErrorStackDepth(t *testing.T) {
+	err := Trace("test")
+	frames := err.Stack()
+	if len(frames) > currentConfig.stackDepth {
+		t.Errorf("Stack depth %d exceeds configured max %d", len(frames), currentConfig.stackDepth)
+	}
+}
+
+// TestErrorTransform verifies Transform behavior for nil, non-*Error, and *Error inputs.
+func TestErrorTransform(t *testing.T) {
+	// Test nil input.
+	t.Run("NilError", func(t *testing.T) {
+		result := Transform(nil, func(e *Error) {})
+		if result != nil {
+			t.Error("Should return nil for nil input")
+		}
+	})
+
+	// Test standard library error.
+	t.Run("NonErrorType", func(t *testing.T) {
+		stdErr := errors.New("standard")
+		transformed := Transform(stdErr, func(e *Error) {})
+		if transformed == nil {
+			t.Error("Should not return nil for non-nil input")
+		}
+		if transformed.Error() != "standard" {
+			t.Errorf("Should preserve original message, got %q, want %q", transformed.Error(), "standard")
+		}
+		if transformed == stdErr {
+			t.Error("Should return a new *Error, not the original")
+		}
+	})
+
+	// Test transforming *Error.
+	t.Run("TransformError", func(t *testing.T) {
+		orig := New("original")
+		defer orig.Free()
+		transformed := Transform(orig, func(e *Error) {
+			e.With("key", "value")
+		})
+		defer transformed.Free()
+
+		if transformed == orig {
+			t.Error("Should return a copy, not the original")
+		}
+		if transformed.Error() != "original" {
+			t.Errorf("Should preserve original message, got %q, want %q", transformed.Error(), "original")
+		}
+		if transformed.Context()["key"] != "value" {
+			t.Error("Should apply transformations, context missing 'key'='value'")
+		}
+	})
+}
+
+// TestErrorWithout verifies that Without clears volatile fields on a copy so
+// otherwise-identical errors compare equal, leaving the original untouched.
+func TestErrorWithout(t *testing.T) {
+	a := Trace("db failure").With("table", "users", "trace_id", "t-1").Increment()
+	defer a.Free()
+	b := New("db failure").With("table", "users", "trace_id", "t-2")
+	defer b.Free()
+
+	na := a.Without("stack", "count", "trace_id")
+	defer na.Free()
+	nb := b.Without("stack", "count", "trace_id")
+	defer nb.Free()
+
+	ja, _ := json.Marshal(na)
+	jb, _ := json.Marshal(nb)
+	if string(ja) != string(jb) {
+		t.Errorf("Without() results differ:\n%s\n%s", ja, jb)
+	}
+	if na.Stack() != nil || na.Count() != 0 || na.HasContextKey("trace_id") {
+		t.Error("Without() did not clear stack, count, and context key")
+	}
+	if !na.HasContextKey("table") {
+		t.Error("Without() removed an unrelated context key")
+	}
+	if a.Stack() == nil || a.Count() != 1 || !a.HasContextKey("trace_id") {
+		t.Error("Without() modified the original error")
+	}
+
+	all := a.Without("context")
+	defer all.Free()
+	if len(all.Context()) != 0 {
+		t.Errorf("Without(\"context\") left context %v", all.Context())
+	}
+}
+
+// TestErrorAnnotate verifies that Annotate prepends to the message in place.
+func TestErrorAnnotate(t *testing.T) {
+	err := New("x")
+	defer err.Free()
+	if got := err.Annotate("y"); got != err {
+		t.Error("Annotate should return the same error")
+	}
+	if err.Error() != "y: x" {
+		t.Errorf("Expected %q, got %q", "y: x", err.Error())
+	}
+	if err.Annotate("z").Error() != "z: y: x" {
+		t.Errorf("Expected stacked annotation, got %q", err.Error())
+	}
+
+	wrapped := New("load config").Wrap(errors.New("not found")).Annotate("startup")
+	defer wrapped.Free()
+	if wrapped.Error() != "startup: load config: not found" {
+		t.Errorf("Unexpected wrapped message %q", wrapped.Error())
+	}
+
+	named := Named("ErrTimeout").Annotate("dial")
+	defer named.Free()
+	if named.Error() != "dial: ErrTimeout" {
+		t.Errorf("Expected name as base, got %q", named.Error())
+	}
+	if named.Name() != "ErrTimeout" {
+		t.Errorf("Annotate should not change the name, got %q", named.Name())
+	}
+}
+
+// TestErrorWithStackIf verifies that a stack is captured only when the condition holds.
+func TestErrorWithStackIf(t *testing.T) {
+	off := New("no stack").WithStackIf(false)
+	defer off.Free()
+	if len(off.Stack()) != 0 {
+		t.Errorf("Expected no stack when cond is false, got %d frames", len(off.Stack()))
+	}
+
+	on := New("with stack").WithStackIf(true)
+	defer on.Free()
+	stack := on.Stack()
+	if len(stack) == 0 {
+		t.Fatal("Expected stack when cond is true")
+	}
+	if !strings.Contains(stack[0], "TestErrorWithStackIf") {
+		t.Errorf("Expected first frame to be the caller, got %q", stack[0])
+	}
+}
+
+// TestErrorWriteJSON verifies that WriteJSON streams the same JSON as MarshalJSON
+// and that a failed write does not poison later encodes.
+func TestErrorWriteJSON(t *testing.T) {
+	err := New("outer").WithCode(502).With("user", "alice").
+		Wrap(New("inner").With("attempt", 3).Wrap(errors.New("root")))
+	defer err.Free()
+
+	want, mErr := err.MarshalJSON()
+	if mErr != nil {
+		t.Fatalf("MarshalJSON failed: %v", mErr)
+	}
+	var buf bytes.Buffer
+	if wErr := err.WriteJSON(&buf); wErr != nil {
+		t.Fatalf("WriteJSON failed: %v", wErr)
+	}
+	if got := strings.TrimSuffix(buf.String(), "\n"); got != string(want) {
+		t.Errorf("WriteJSON mismatch.\nGot:  %s\nWant: %s", got, want)
+	}
+
+	if wErr := err.WriteJSON(failingWriter{}); wErr == nil {
+		t.Error("Expected write error to be returned")
+	}
+	buf.Reset()
+	if wErr := err.WriteJSON(&buf); wErr != nil || buf.Len() == 0 {
+		t.Errorf("WriteJSON after a failed write = %v, %d bytes", wErr, buf.Len())
+	}
+}
+
+// failingWriter is an io.Writer whose writes always fail.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+// TestErrorContextKeys verifies that ContextKeys lists keys from both small
+// and map-based context, sorted and without duplicates.
+func TestErrorContextKeys(t *testing.T) {
+	empty := New("empty")
+	defer empty.Free()
+	if keys := empty.ContextKeys(); keys != nil {
+		t.Errorf("Expected nil keys, got %v", keys)
+	}
+
+	small := New("small").With("b", 1, "a", 2).With("b", 3)
+	defer small.Free()
+	if keys := small.ContextKeys(); !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %v", keys)
+	}
+	_ = small.Context() // Materialize the map; keys must not repeat
+	if keys := small.ContextKeys(); !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Errorf("Expected [a b] after Context(), got %v", keys)
+	}
+
+	large := New("large").With("k1", 1, "k2", 2, "k3", 3).With("k4", 4, "k5", 5)
+	defer large.Free()
+	want := []string{"k1", "k2", "k3", "k4", "k5"}
+	if keys := large.ContextKeys(); !reflect.DeepEqual(keys, want) {
+		t.Errorf("Expected %v, got %v", want, keys)
+	}
+}
+
+// TestErrorWithCaller verifies that WithCaller records the calling test function
+// and that the site is exposed through Caller, Format, and JSON.
+func TestErrorWithCaller(t *testing.T) {
+	err := New("caller test").WithCaller()
+	defer err.Free()
+
+	caller := err.Caller()
+	if !strings.Contains(caller, "errors_test.go:") || !strings.HasSuffix(caller, "TestErrorWithCaller") {
+		t.Fatalf("Expected caller in TestErrorWithCaller, got %q", caller)
+	}
+	if len(err.Stack()) != 0 {
+		t.Error("WithCaller should not capture a full stack")
+	}
+	if !strings.Contains(err.Format(), "caller: "+caller) {
+		t.Errorf("Format() missing caller, got:\n%s", err.Format())
+	}
+	data, jErr := json.Marshal(err)
+	if jErr != nil {
+		t.Fatalf("MarshalJSON failed: %v", jErr)
+	}
+	if !strings.Contains(string(data), `"caller":"`+caller+`"`) {
+		t.Errorf("JSON missing caller, got %s", data)
+	}
+
+	plain := New("no caller")
+	defer plain.Free()
+	if plain.Caller() != "" {
+		t.Errorf("Expected empty caller, got %q", plain.Caller())
+	}
+}
+
+// TestErrorStackN verifies that StackN returns the leading frames of a deeper trace.
+func TestErrorStackN(t *testing.T) {
+	var deep func(depth int) *Error
+	deep = func(depth int) *Error {
+		if depth == 0 {
+			return Trace("deep")
+		}
+		return deep(depth - 1)
+	}
+	err := deep(5)
+	defer err.Free()
+
+	full := err.Stack()
+	if len(full) <= 3 {
+		t.Fatalf("Expected a deeper trace, got %d frames", len(full))
+	}
+	top := err.StackN(3)
+	if !reflect.DeepEqual(top, full[:3]) {
+		t.Errorf("StackN(3) = %v, want %v", top, full[:3])
+	}
+	if got := err.StackN(len(full) + 10); !reflect.DeepEqual(got, full) {
+		t.Errorf("StackN beyond depth should return all %d frames, got %d", len(full), len(got))
+	}
+	if err.StackN(0) != nil {
+		t.Error("StackN(0) should return nil")
+	}
+
+	remote := New("remote").SetStackStrings([]string{"a", "b", "c", "d"})
+	defer remote.Free()
+	if got := remote.StackN(2); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("StackN(2) with stack strings = %v, want [a b]", got)
+	}
+}
+
+// TestErrorReplaceCause verifies that ReplaceCause swaps the cause in place,
+// including the embedded text of errors created with Newf and %w.
+func TestErrorReplaceCause(t *testing.T) {
+	a := errors.New("password=hunter2 rejected")
+	b := errors.New("credentials rejected")
+
+	err := New("login failed").Wrap(a)
+	defer err.Free()
+	if got := err.ReplaceCause(b); got != err {
+		t.Error("ReplaceCause should return the same error")
+	}
+	if err.Unwrap() != b {
+		t.Errorf("Unwrap() = %v, want %v", err.Unwrap(), b)
+	}
+	if err.Error() != "login failed: credentials rejected" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+
+	wrapped := Newf("login failed: %w", a)
+	defer wrapped.Free()
+	sanitized := wrapped.Copy().ReplaceCause(b)
+	defer sanitized.Free()
+	if sanitized.Error() != "login failed: credentials rejected" {
+		t.Errorf("Newf message should embed the new cause, got %q", sanitized.Error())
+	}
+	if !errors.Is(sanitized, b) || errors.Is(sanitized, a) {
+		t.Error("Sanitized chain should contain only the new cause")
+	}
+	if wrapped.Unwrap() != a {
+		t.Error("ReplaceCause on a copy should not modify the original")
+	}
+
+	if err.ReplaceCause(nil).Unwrap() != nil {
+		t.Error("ReplaceCause(nil) should remove the cause")
+	}
+}
+
+// TestErrorIsCategory verifies category checks on the error and across its chain.
+func TestErrorIsCategory(t *testing.T) {
+	const categoryDatabase ErrorCategory = "database"
+	dbErr := New("query failed").WithCategory(categoryDatabase)
+	defer dbErr.Free()
+	if !dbErr.IsCategory(categoryDatabase) {
+		t.Error("IsCategory should match the error's category")
+	}
+	if dbErr.IsCategory("network") || dbErr.IsCategory("") {
+		t.Error("IsCategory should not match other categories")
+	}
+
+	outer := New("handler failed").WithCategory("http").Wrap(dbErr)
+	defer outer.Free()
+	if outer.IsCategory(categoryDatabase) {
+		t.Error("IsCategory should not check causes")
+	}
+	if !HasCategory(outer, categoryDatabase) || !HasCategory(outer, "http") {
+		t.Error("HasCategory should find categories anywhere in the chain")
+	}
+	if HasCategory(outer, "network") || HasCategory(errors.New("plain"), categoryDatabase) {
+		t.Error("HasCategory should not match absent categories")
+	}
+}
+
+// TestErrorMerge verifies that Merge unions context, keeps a non-zero code,
+// and appends the other error's message.
+func TestErrorMerge(t *testing.T) {
+	base := New("save failed").With("user", "alice").With("shared", "base")
+	defer base.Free()
+	other := New("invalid email").With("field", "email").With("shared", "other").WithCode(422)
+	defer other.Free()
+
+	if got := base.Merge(other); got != base {
+		t.Fatal("Merge should return the receiver")
+	}
+	ctx := base.Context()
+	if ctx["user"] != "alice" || ctx["field"] != "email" {
+		t.Errorf("Merge context = %v, want keys from both errors", ctx)
+	}
+	if ctx["shared"] != "base" {
+		t.Errorf("Merge should keep the receiver's value for shared keys, got %v", ctx["shared"])
+	}
+	if base.Code() != 422 {
+		t.Errorf("Merge code = %d, want 422 from other", base.Code())
+	}
+	if base.Error() != "save failed; invalid email" {
+		t.Errorf("Merge message = %q, want %q", base.Error(), "save failed; invalid email")
+	}
+	if _, ok := other.Context()["user"]; ok {
+		t.Error("Merge should not modify other")
+	}
+
+	coded := New("a").WithCode(400)
+	defer coded.Free()
+	coded.Merge(New("b").WithCode(500))
+	if coded.Code() != 400 {
+		t.Errorf("Merge should keep the receiver's non-zero code, got %d", coded.Code())
+	}
+	if coded.Merge(nil) != coded || coded.Error() != "a; b" {
+		t.Errorf("Merge(nil) should be a no-op, got %q", coded.Error())
+	}
+}
+
+// TestErrorStackFilter verifies that Config.StackFilter decides which frames
+// Stack and FastStack keep, in place of the internal-frame rules.
+func TestErrorStackFilter(t *testing.T) {
+	originalConfig := currentConfig
+	defer func() { currentConfig = originalConfig }()
+
+	Configure(Config{StackFilter: func(frame runtime.Frame) bool {
+		return !strings.HasPrefix(frame.Function, "runtime.")
+	}})
+	err := Trace("filtered")
+	defer err.Free()
+
+	stack := err.Stack()
+	if len(stack) == 0 {
+		t.Fatal("Stack() should not be empty")
+	}
+	for _, frame := range stack {
+		if strings.HasPrefix(frame, "runtime.") {
+			t.Errorf("Stack() should not contain runtime frames, got %q", frame)
+		}
+	}
+	if !strings.Contains(stack[len(stack)-1], "testing.tRunner") {
+		t.Errorf("Stack() should end at testing.tRunner once runtime.goexit is dropped, got %v", stack)
+	}
+	if fast := err.FastStack(); len(fast) != len(stack) {
+		t.Errorf("FastStack() kept %d frames, want %d like Stack()", len(fast), len(stack))
+	}
+
+	Configure(Config{})
+	if stack := err.Stack(); !strings.HasPrefix(stack[len(stack)-1], "runtime.goexit") {
+		t.Errorf("Stack() without a filter should keep runtime frames, got %v", stack)
+	}
+}
+
+// TestErrorCodeOrDefault verifies that CodeOrDefault distinguishes an unset
+// code from an explicit zero.
+func TestErrorCodeOrDefault(t *testing.T) {
+	unset := New("no code")
+	defer unset.Free()
+	if got := unset.CodeOrDefault(500); got != 500 {
+		t.Errorf("CodeOrDefault() without a code = %d, want 500", got)
+	}
+
+	zero := New("zero code").WithCode(0)
+	defer zero.Free()
+	if got := zero.CodeOrDefault(500); got != 0 {
+		t.Errorf("CodeOrDefault() after WithCode(0) = %d, want 0", got)
+	}
+	if got := zero.Copy().CodeOrDefault(500); got != 0 {
+		t.Errorf("Copy() should preserve an explicit zero code, got %d", got)
+	}
+	if got := zero.Transform(func(e *Error) {}).CodeOrDefault(500); got != 0 {
+		t.Errorf("Transform() should preserve an explicit zero code, got %d", got)
+	}
+
+	coded := New("coded").WithCode(404)
+	defer coded.Free()
+	if got := coded.CodeOrDefault(500); got != 404 {
+		t.Errorf("CodeOrDefault() = %d, want 404", got)
+	}
+	if got := coded.Without("code").CodeOrDefault(500); got != 500 {
+		t.Errorf("CodeOrDefault() after Without(code) = %d, want 500", got)
+	}
+
+	var nilErr *Error
+	if got := nilErr.CodeOrDefault(500); got != 500 {
+		t.Errorf("CodeOrDefault() on nil = %d, want 500", got)
+	}
+}
+
+// TestErrorStackContains verifies that StackContains searches the filtered stack.
+func TestErrorStackContains(t *testing.T) {
+	err := Trace("traced")
+	defer err.Free()
+	if !err.StackContains("TestErrorStackContains") {
+		t.Errorf("StackContains() should find the test function in %v", err.Stack())
+	}
+	if err.StackContains("no.such.function") {
+		t.Error("StackContains() should not match absent frames")
+	}
+
+	plain := New("no stack")
+	defer plain.Free()
+	if plain.StackContains("") {
+		t.Error("StackContains() should be false without a stack")
+	}
+
+	remote := New("remote").SetStackStrings([]string{"svc.Handle handler.go:12"})
+	defer remote.Free()
+	if !remote.StackContains("svc.Handle") {
+		t.Error("StackContains() should search frames set via SetStackStrings")
+	}
+}
+
+// TestErrorMessageSeparator verifies that Config.MessageSeparator joins
+// chained messages, and that Newf with %w keeps fmt.Errorf formatting.
+func TestErrorMessageSeparator(t *testing.T) {
+	originalConfig := currentConfig
+	defer func() { currentConfig = originalConfig }()
+
+	Configure(Config{MessageSeparator: " -> "})
+	err := New("request failed").Wrap(New("query failed").Wrap(errors.New("connection refused")))
+	defer err.Free()
+	if want := "request failed -> query failed -> connection refused"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	wrapped := Newf("read: %w", errors.New("EOF"))
+	defer wrapped.Free()
+	if wrapped.Error() != "read: EOF" {
+		t.Errorf("Newf() with %%w should ignore the separator, got %q", wrapped.Error())
+	}
+
+	Configure(Config{})
+	if want := "request failed: query failed: connection refused"; err.Error() != want {
+		t.Errorf("Error() with default separator = %q, want %q", err.Error(), want)
+	}
+}
+
+// TestErrorRender verifies that Render fills {key} placeholders from context.
+func TestErrorRender(t *testing.T) {
+	err := New("lookup failed").WithTemplate("user {user_id} not found in {region}").With("user_id", 42)
+	defer err.Free()
+	if got, want := err.Render(), "user 42 not found in {region}"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	err.With("region", "eu-west")
+	if got, want := err.Render(), "user 42 not found in eu-west"; got != want {
+		t.Errorf("Render() after With = %q, want %q", got, want)
+	}
+
+	odd := New("x").WithTemplate("{} and {unclosed").With("", "empty")
+	defer odd.Free()
+	if got := odd.Render(); got != "{} and {unclosed" {
+		t.Errorf("Render() should keep malformed placeholders, got %q", got)
+	}
+
+	plain := New("no template")
+	defer plain.Free()
+	if plain.Render() != "no template" {
+		t.Errorf("Render() without a template = %q, want Error()", plain.Render())
+	}
+}
+
+// TestErrorSeverity verifies WithSeverity, its ordering, and that Copy and
+// Reset handle the field.
+func TestErrorSeverity(t *testing.T) {
+	err := New("disk full")
+	if err.Severity() != SeverityNone {
+		t.Errorf("Severity() without WithSeverity = %v, want none", err.Severity())
+	}
+	err.WithSeverity(SeverityFatal)
+	if err.Severity() != SeverityFatal || err.Severity().String() != "fatal" {
+		t.Errorf("Severity() = %v, want fatal", err.Severity())
+	}
+	if !(SeverityFatal > SeverityCritical && SeverityWarning > SeverityInfo) {
+		t.Error("severities should increase in seriousness")
+	}
+	if cp := err.Copy(); cp.Severity() != SeverityFatal {
+		t.Errorf("Copy() severity = %v, want fatal", cp.Severity())
+	}
+	err.Reset()
+	if err.Severity() != SeverityNone {
+		t.Errorf("Reset() left severity %v", err.Severity())
+	}
+}
+
+// TestErrorHashKey verifies that equal-content errors share a HashKey and
+// that name, code, category, and message each change it.
+func TestErrorHashKey(t *testing.T) {
+	build := func() *Error {
+		return Named("DBError").Msgf("query failed").WithCode(500).WithCategory("database").With("id", 1)
+	}
+	a, b := build(), build().With("id", 2)
+	defer a.Free()
+	defer b.Free()
+	if a.HashKey() != b.HashKey() {
+		t.Error("HashKey() should match for errors with equal content")
+	}
+
+	variants := map[string]*Error{
+		"name":     build().WithName("Other"),
+		"code":     build().WithCode(503),
+		"category": build().WithCategory("network"),
+		"message":  build().Msgf("insert failed"),
+		"cause":    build().Wrap(errors.New("timeout")),
+	}
+	for field, v := range variants {
+		if v.HashKey() == a.HashKey() {
+			t.Errorf("HashKey() should differ when %s differs", field)
+		}
+		v.Free()
+	}
+
+	counts := map[uint64]int{}
+	counts[a.HashKey()]++
+	counts[b.HashKey()]++
+	if len(counts) != 1 {
+		t.Errorf("HashKey() map aggregation = %v, want a single key", counts)
+	}
+}
+
+// TestErrorWrapMany verifies that errors.Is and errors.As find each of
+// several causes set by WrapMany.
+func TestErrorWrapMany(t *testing.T) {
+	errDebit := errors.New("debit failed")
+	errRollback := &customError{msg: "rollback failed"}
+	err := New("transfer failed").WrapMany(errDebit, nil, errRollback)
+	defer err.Free()
+
+	if !errors.Is(err, errDebit) {
+		t.Error("errors.Is should find the first cause")
+	}
+	var target *customError
+	if !errors.As(err, &target) || target != errRollback {
+		t.Error("errors.As should find the second cause")
+	}
+	multi, ok := err.Unwrap().(interface{ Unwrap() []error })
+	if !ok || len(multi.Unwrap()) != 2 {
+		t.Fatalf("Unwrap() should expose both causes, got %v", err.Unwrap())
+	}
+	if !strings.HasPrefix(err.Error(), "transfer failed: ") {
+		t.Errorf("Error() = %q, want the message followed by the causes", err.Error())
+	}
+
+	single := New("outer").WrapMany(nil, errDebit)
+	defer single.Free()
+	if single.Unwrap() != errDebit {
+		t.Errorf("WrapMany with one cause should behave like Wrap, got %v", single.Unwrap())
+	}
+	if New("none").WrapMany().Unwrap() != nil {
+		t.Error("WrapMany with no causes should leave the cause unset")
+	}
+
+	// Distinct causes sharing a message must all be kept.
+	writeA, writeB := errors.New("write failed"), errors.New("write failed")
+	ioA, ioB := Named("ErrDisk").Msgf("io"), Named("ErrNet").Msgf("io")
+	same := New("flush failed").WrapMany(writeA, writeB, ioA, ioB)
+	defer same.Free()
+	for i, cause := range []error{writeA, writeB, ioA, ioB} {
+		if !errors.Is(same, cause) {
+			t.Errorf("errors.Is should find cause %d among causes sharing a message", i)
+		}
+	}
+	var named *Error
+	if !errors.As(same.Unwrap(), &named) || named != ioA {
+		t.Errorf("errors.As should find the first named cause, got %v", named)
+	}
+}
+
+// TestErrorIsTransient verifies that IsTransient relies on the explicit marker
+// and category, not on words in the message.
+func TestErrorIsTransient(t *testing.T) {
+	mentionsRetry := New("invalid retry policy in config")
+	defer mentionsRetry.Free()
+	if mentionsRetry.IsTransient() {
+		t.Error("IsTransient() should ignore a message mentioning retry")
+	}
+	if !mentionsRetry.WithTransient().IsTransient() {
+		t.Error("IsTransient() should be true once marked with WithTransient")
+	}
+
+	network := New("dial failed").WithCategory("network")
+	defer network.Free()
+	if !network.IsTransient() {
+		t.Error("IsTransient() should be true for the network category")
+	}
+
+	outer := New("sync failed").WithCategory("validation").Wrap(network)
+	defer outer.Free()
+	if !outer.IsTransient() {
+		t.Error("IsTransient() should check wrapped errors")
+	}
+	if New("bad input").WithCategory("validation").IsTransient() {
+		t.Error("IsTransient() should be false for other categories")
+	}
+}
+
+// TestErrorCompare verifies priority ordering by severity, code, then name.
+func TestErrorCompare(t *testing.T) {
+	fatal := New("a").WithSeverity(SeverityFatal).WithCode(400)
+	warning := New("b").WithSeverity(SeverityWarning).WithCode(500)
+	server := New("c").WithCode(500)
+	client := New("d").WithCode(400)
+	named := Named("Alpha").WithCode(400)
+
+	if fatal.Compare(warning) != -1 || warning.Compare(fatal) != 1 {
+		t.Error("a fatal error should come before a warning regardless of code")
+	}
+	if server.Compare(client) != -1 {
+		t.Error("a 500 should come before a 400")
+	}
+	if named.Compare(client) != 1 || client.Compare(New("e").WithCode(400)) != 0 {
+		t.Error("equal severity and code should be ordered by name")
+	}
+	if client.Compare(nil) != -1 || (*Error)(nil).Compare(client) != 1 {
+		t.Error("nil should sort last")
+	}
+
+	errs := []*Error{client, server, nil, warning, fatal}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Compare(errs[j]) < 0 })
+	if want := []*Error{fatal, warning, server, client, nil}; !reflect.DeepEqual(errs, want) {
+		t.Errorf("sorted order = %v, want %v", errs, want)
+	}
+}
+
+// pkgFrame and pkgStackTrace mirror the pkg/errors stack types.
+type pkgFrame uintptr
+type pkgStackTrace []pkgFrame
+
+// pkgStyleError is a third-party error exposing a pkg/errors-style stack.
+type pkgStyleError struct{ pcs []uintptr }
+
+func (e *pkgStyleError) Error() string { return "third-party failure" }
+
+func (e *pkgStyleError) StackTrace() pkgStackTrace {
+	trace := make(pkgStackTrace, len(e.pcs))
+	for i, pc := range e.pcs {
+		trace[i] = pkgFrame(pc)
+	}
+	return trace
+}
+
+// newPkgStyleError records its caller's stack like pkg/errors.New.
+func newPkgStyleError() error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs)
+	return &pkgStyleError{pcs: pcs[:n]}
+}
+
+// TestErrorWithStackFrom verifies that WithStackFrom adopts the stack of a
+// third-party or wrapped error instead of the conversion site.
+func TestErrorWithStackFrom(t *testing.T) {
+	third := fmt.Errorf("context: %w", newPkgStyleError())
+	err := New("converted").Wrap(third).WithStackFrom(third)
+	defer err.Free()
+	if !err.StackContains("newPkgStyleError") {
+		t.Errorf("Stack() should start at the third-party origin, got %v", err.Stack())
+	}
+
+	inner := Trace("inner")
+	defer inner.Free()
+	outer := New("outer").WithStackFrom(New("wrapper").Wrap(inner))
+	defer outer.Free()
+	if !reflect.DeepEqual(outer.Stack(), inner.Stack()) {
+		t.Errorf("Stack() = %v, want the wrapped *Error's stack %v", outer.Stack(), inner.Stack())
+	}
+
+	plain := New("plain").WithStackFrom(errors.New("no stack"))
+	defer plain.Free()
+	if plain.Stack() != nil {
+		t.Errorf("WithStackFrom() without a stack should leave the error unchanged, got %v", plain.Stack())
+	}
+}
+
+// TestErrorDepth verifies that Depth counts every error in the chain and
+// stops on cycles.
+func TestErrorDepth(t *testing.T) {
+	root := errors.New("disk full")
+	mid := New("write failed").Wrap(root)
+	top := New("save failed").Wrap(mid)
+	defer mid.Free()
+	defer top.Free()
+	if got := top.Depth(); got != 3 {
+		t.Errorf("Depth() = %d, want 3", got)
+	}
+	if got := New("alone").Depth(); got != 1 {
+		t.Errorf("Depth() without a cause = %d, want 1", got)
+	}
+	if got := (*Error)(nil).Depth(); got != 0 {
+		t.Errorf("Depth() on nil = %d, want 0", got)
+	}
+
+	a, b := New("a"), New("b")
+	a.Wrap(b)
+	b.Wrap(a)
+	if got := a.Depth(); got != 2 {
+		t.Errorf("Depth() on a cycle = %d, want 2", got)
+	}
+}
+
+// TestErrorCanonicalJSON verifies that CanonicalJSON output is byte-identical
+// across calls and copies, with context keys sorted.
+func TestErrorCanonicalJSON(t *testing.T) {
+	build := func() *Error {
+		err := New("sync failed").WithCode(500)
+		for _, k := range []string{"zeta", "alpha", "mid", "beta", "omega", "gamma"} {
+			err.With(k, map[string]int{"z": 1, "a": 2, k: 3})
+		}
+		return err.Wrap(New("inner").With("y", 1, "b", 2, "x", 3, "a", 4, "c", 5))
+	}
+	first := build()
+	defer first.Free()
+	want, err := first.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON() error: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		e := build()
+		got, _ := e.CanonicalJSON()
+		e.Free()
+		if !bytes.Equal(got, want) {
+			t.Fatalf("CanonicalJSON() not stable:\n%s\n%s", got, want)
+		}
+	}
+	if i, j := bytes.Index(want, []byte(`"alpha"`)), bytes.Index(want, []byte(`"zeta"`)); i < 0 || i > j {
+		t.Errorf("CanonicalJSON() context keys should be sorted, got %s", want)
+	}
+}
+
+// TestErrorInheritContext verifies that InheritContext copies an inner error's
+// context onto a wrapper without overwriting existing keys.
+func TestErrorInheritContext(t *testing.T) {
+	inner := New("query failed").With("table", "orders", "shard", 3, "op", "inner")
+	defer inner.Free()
+	outer := New("checkout failed").With("op", "outer").Wrap(inner).InheritContext(inner)
+	defer outer.Free()
+
+	ctx := outer.Context()
+	if ctx["table"] != "orders" || ctx["shard"] != 3 {
+		t.Errorf("InheritContext() context = %v, want inner keys", ctx)
+	}
+	if ctx["op"] != "outer" {
+		t.Errorf("InheritContext() should keep existing keys, got op=%v", ctx["op"])
+	}
+	if outer.InheritContext(errors.New("plain")) != outer || outer.InheritContext(nil) != outer {
+		t.Error("InheritContext() should ignore non-*Error sources")
+	}
+}
+
+// TestErrorFilterContext verifies that FilterContext keeps only the listed keys.
+func TestErrorFilterContext(t *testing.T) {
+	err := New("failed").With("user", "alice", "token", "secret", "path", "/a")
+	defer err.Free()
+
+	filtered := err.FilterContext("user", "missing")
+	defer filtered.Free()
+	if got := filtered.Context(); !reflect.DeepEqual(got, map[string]interface{}{"user": "alice"}) {
+		t.Errorf("FilterContext() context = %v, want only user", got)
+	}
+	if len(err.Context()) != 3 {
+		t.Errorf("FilterContext() modified the original context: %v", err.Context())
+	}
+	if filtered.Error() != "failed" {
+		t.Errorf("FilterContext() message = %q, want %q", filtered.Error(), "failed")
+	}
+	if got := err.FilterContext().Context(); len(got) != 0 {
+		t.Errorf("FilterContext() with no keys = %v, want empty", got)
+	}
+}
+
+// TestErrorToStdError verifies that ToStdError returns a plain error with the
+// full message and no link back to the original chain.
+func TestErrorToStdError(t *testing.T) {
+	cause := New("connection reset")
+	err := New("query failed").WithCode(503).With("table", "users").Wrap(cause)
+	defer err.Free()
+
+	std := err.ToStdError()
+	if _, ok := std.(*Error); ok {
+		t.Fatal("ToStdError() returned an *Error")
+	}
+	if std.Error() != "query failed: connection reset" {
+		t.Errorf("ToStdError() message = %q, want %q", std.Error(), "query failed: connection reset")
+	}
+	if errors.Unwrap(std) != nil || errors.Is(std, cause) {
+		t.Error("ToStdError() should sever the error chain")
+	}
+	var e *Error
+	if e.ToStdError() != nil {
+		t.Error("ToStdError() on nil receiver should return nil")
+	}
+}
+
+// TestErrorMaxJSONValueLen verifies that MarshalJSON truncates long context
+// values to Config.MaxJSONValueLen without modifying the error itself.
+func TestErrorMaxJSONValueLen(t *testing.T) {
+	originalConfig := currentConfig
+	defer func() { currentConfig = originalConfig }()
+	Configure(Config{MaxJSONValueLen: 256})
+
+	body := strings.Repeat("x", 10*1024)
+	err := New("bad request").With("body", body, "user", "alice", "raw", []byte(body), "n", 7)
+	defer err.Free()
+
+	data, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatalf("MarshalJSON() error: %v", jsonErr)
+	}
+	var got struct {
+		Context map[string]interface{} `json:"context"`
+	}
+	if jsonErr := json.Unmarshal(data, &got); jsonErr != nil {
+		t.Fatalf("Unmarshal() error: %v", jsonErr)
+	}
+	want := strings.Repeat("x", 256) + "…(truncated)"
+	if got.Context["body"] != want || got.Context["raw"] != want {
+		t.Errorf("MarshalJSON() did not truncate long values: body %d bytes, raw %v", len(fmt.Sprint(got.Context["body"])), got.Context["raw"] == want)
+	}
+	if got.Context["user"] != "alice" || got.Context["n"] != float64(7) {
+		t.Errorf("MarshalJSON() changed short values: %v", got.Context)
+	}
+	if v, _ := err.contextValue("body"); v != body {
+		t.Error("MarshalJSON() modified the error's context")
+	}
+
+	if cut := truncateContextValues(map[string]interface{}{"s": "héllo"}, 2)["s"]; cut != "h…(truncated)" {
+		t.Errorf("truncateContextValues() split a rune: %q", cut)
+	}
+}
+
+// TestErrorWithStackTrace verifies that a pre-captured stack can be applied to
+// several errors and that freeing them leaves the caller's slice intact.
+func TestErrorWithStackTrace(t *testing.T) {
+	pcs := make([]uintptr, 32)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+	saved := append([]uintptr(nil), pcs...)
+
+	errA := New("a").WithStackTrace(pcs)
+	errB := New("b").WithStackTrace(pcs)
+	stack := errA.Stack()
+	if len(stack) == 0 || !strings.Contains(stack[0], "TestErrorWithStackTrace") {
+		t.Errorf("WithStackTrace() stack = %v, want it to start in the test", stack)
+	}
+	if !reflect.DeepEqual(errB.Stack(), stack) {
+		t.Errorf("WithStackTrace() stacks differ: %v vs %v", errB.Stack(), stack)
+	}
+
+	errA.Free()
+	errB.Free()
+	for i := 0; i < 10; i++ {
+		New("reuse").WithStack().Free()
+	}
+	if !reflect.DeepEqual(pcs, saved) {
+		t.Error("Free() modified the caller's program counters")
+	}
+
+	cleared := New("c").WithStackTrace(pcs).WithStackTrace(nil)
+	defer cleared.Free()
+	if len(cleared.Stack()) != 0 {
+		t.Errorf("WithStackTrace(nil) stack = %v, want empty", cleared.Stack())
+	}
+}
+
+// TestErrorNilReceiver verifies that accessors return zero values and modifiers
+// are no-ops on a nil *Error instead of panicking.
+func TestErrorNilReceiver(t *testing.T) {
+	var e *Error
+
+	if e.Code() != 0 || e.Count() != 0 || e.Name() != "" || e.Category() != "" {
+		t.Errorf("nil accessors = %d/%d/%q/%q, want zero values", e.Code(), e.Count(), e.Name(), e.Category())
+	}
+	if e.Context() != nil || e.ContextKeys() != nil || e.Stack() != nil || e.Unwrap() != nil {
+		t.Error("nil Context/ContextKeys/Stack/Unwrap should return nil")
+	}
+	if e.Has() || e.HasContextKey("k") || e.IsTransient() || e.Severity() != SeverityNone {
+		t.Error("nil predicates should report false")
+	}
+	if e.Error() != "<nil>" {
+		t.Errorf("nil Error() = %q, want %q", e.Error(), "<nil>")
+	}
+	if e.Err() != nil {
+		t.Error("nil Err() should return an untyped nil error")
+	}
+	if got := e.With("k", "v").WithCode(400).WithName("X").Msgf("m").Wrap(New("cause")).WithStack(); got != nil {
+		t.Errorf("nil modifiers = %v, want nil", got)
+	}
+	if e.Copy() != nil {
+		t.Error("nil Copy() should return nil")
+	}
+	if data, err := e.MarshalJSON(); err != nil || string(data) != "null" {
+		t.Errorf("nil MarshalJSON() = %q, %v, want null", data, err)
+	}
+	if _, err := e.MarshalBinary(); err == nil {
+		t.Error("nil MarshalBinary() should return an error")
+	}
+	e.Reset()
+	e.Free()
+}
+
+// TestErrorTags verifies adding, reading, copying, and serializing tags.
+func TestErrorTags(t *testing.T) {
+	err := New("charge failed").WithTags("payment", "external", "", "payment").WithTags("stripe")
+	defer err.Free()
+
+	if got, want := err.Tags(), []string{"payment", "external", "stripe"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tags() = %v, want %v", got, want)
+	}
+	if !err.HasTag("external") || err.HasTag("internal") {
+		t.Error("HasTag() reported wrong membership")
+	}
+	err.Tags()[0] = "changed"
+	if !err.HasTag("payment") {
+		t.Error("Tags() should return a copy")
+	}
+
+	copied := err.Copy().WithTags("copy")
+	defer copied.Free()
+	if err.HasTag("copy") || !copied.HasTag("stripe") {
+		t.Errorf("Copy() should copy tags independently: original %v, copy %v", err.Tags(), copied.Tags())
+	}
+
+	data, _ := json.Marshal(err)
+	if !strings.Contains(string(data), `"tags":["payment","external","stripe"]`) {
+		t.Errorf("MarshalJSON() missing tags: %s", data)
+	}
+	if New("plain").Tags() != nil {
+		t.Error("Tags() without tags should be nil")
+	}
+}
+
+// TestErrorWithCallSite verifies that WithCallSite records the call site and
+// leaves an existing stack alone.
+func TestErrorWithCallSite(t *testing.T) {
+	err := New("lazy").WithCallSite()
+	defer err.Free()
+	stack := err.Stack()
+	if len(stack) != 1 || !strings.Contains(stack[0], "TestErrorWithCallSite") {
+		t.Errorf("WithCallSite() stack = %v, want the test's call site", stack)
+	}
+
+	full := New("full").WithStack()
+	defer full.Free()
+	depth := len(full.Stack())
+	if got := len(full.WithCallSite().Stack()); got != depth {
+		t.Errorf("WithCallSite() replaced an existing stack: %d frames, want %d", got, depth)
+	}
+}
+
+// TestErrorWalkUntil verifies visiting each node of a chain, stopping early,
+// and stopping at a cycle.
+func TestErrorWalkUntil(t *testing.T) {
+	root := errors.New("root")
+	mid := New("mid").Wrap(root)
+	top := New("top").Wrap(mid)
+	defer top.Free()
+	defer mid.Free()
+
+	var visited []error
+	if got := top.WalkUntil(func(e error) bool { visited = append(visited, e); return true }); got != top {
+		t.Errorf("WalkUntil() returned %v, want the receiver", got)
+	}
+	if len(visited) != 3 || visited[0] != top || visited[1] != mid || visited[2] != root {
+		t.Errorf("WalkUntil() visited %v, want top, mid, root", visited)
+	}
+
+	visited = visited[:0]
+	top.WalkUntil(func(e error) bool { visited = append(visited, e); return e != mid })
+	if len(visited) != 2 {
+		t.Errorf("WalkUntil() should stop after mid, visited %v", visited)
+	}
+
+	a, b := New("a"), New("b")
+	a.Wrap(b)
+	b.Wrap(a)
+	n := 0
+	a.WalkUntil(func(error) bool { n++; return n < 100 })
+	if n != 2 {
+		t.Errorf("WalkUntil() on a cycle visited %d nodes, want 2", n)
+	}
+}
+
+// TestErrorCodeClass verifies bucketing codes by their hundreds digit.
+func TestErrorCodeClass(t *testing.T) {
+	for code, want := range map[int]int{200: 2, 302: 3, 404: 4, 429: 4, 500: 5, 503: 5, 0: 0} {
+		err := New("x").WithCode(code)
+		if got := err.CodeClass(); got != want {
+			t.Errorf("CodeClass() for %d = %d, want %d", code, got, want)
+		}
+		err.Free()
+	}
+	if got := New("no code").CodeClass(); got != 0 {
+		t.Errorf("CodeClass() without a code = %d, want 0", got)
+	}
+}
+
+// TestErrorCaptureGoroutineID verifies that errors record the goroutine that
+// created them when Config.CaptureGoroutineID is set.
+func TestErrorCaptureGoroutineID(t *testing.T) {
+	originalConfig := currentConfig
+	defer func() { currentConfig = originalConfig }()
+	Configure(Config{CaptureGoroutineID: true})
+
+	ids := make(chan interface{}, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := New("failed").With("k", "v")
+			defer err.Free()
+			id, _ := err.contextValue(ctxGoroutine)
+			ids <- id
+		}()
+	}
+	wg.Wait()
+	a, b := <-ids, <-ids
+	if a == nil || b == nil || a == b || a == uint64(0) {
+		t.Errorf("Expected two distinct goroutine IDs, got %v and %v", a, b)
+	}
+
+	err := Named("Traced").Copy()
+	defer err.Free()
+	if got := len(err.Context()); got != 1 {
+		t.Errorf("Copy() should keep a single goroutine entry, got %v", err.Context())
+	}
+	data, _ := json.Marshal(err)
+	if !strings.Contains(string(data), `"goroutine":`) || !strings.Contains(err.Format(), "goroutine: ") {
+		t.Errorf("JSON and Format should include the goroutine ID: %s", data)
+	}
+
+	Configure(Config{})
+	plain := New("plain")
+	defer plain.Free()
+	if plain.HasContextKey(ctxGoroutine) {
+		t.Error("Goroutine ID should not be recorded when disabled")
+	}
+}
+
+// TestErrorContextJSON verifies that ContextJSON matches the context field of
+// the full JSON encoding.
+func TestErrorContextJSON(t *testing.T) {
+	err := New("failed").With("user", "alice", "attempts", 3, "html", "<b>", "nested", map[string]int{"b": 2, "a": 1})
+	defer err.Free()
+
+	got, jsonErr := err.ContextJSON()
+	if jsonErr != nil {
+		t.Fatalf("ContextJSON() error: %v", jsonErr)
+	}
+	var full struct {
+		Context json.RawMessage `json:"context"`
+	}
+	data, _ := err.MarshalJSON()
+	if jsonErr := json.Unmarshal(data, &full); jsonErr != nil {
+		t.Fatalf("Unmarshal() error: %v", jsonErr)
+	}
+	if string(got) != string(full.Context) {
+		t.Errorf("ContextJSON() = %s, want MarshalJSON context %s", got, full.Context)
+	}
+
+	empty := New("no context")
+	defer empty.Free()
+	if got, _ := empty.ContextJSON(); string(got) != "{}" {
+		t.Errorf("ContextJSON() without context = %s, want {}", got)
+	}
+}
+
+// TestErrorFreeWithoutPooling verifies that Free clears an error's references
+// even when pooling is disabled.
+func TestErrorFreeWithoutPooling(t *testing.T) {
+	originalConfig := currentConfig
+	defer func() { currentConfig = originalConfig }()
+	Configure(Config{DisablePooling: true})
+
+	body := strings.Repeat("x", 1<<20)
+	err := New("failed").With("body", body, "a", 1, "b", 2, "c", 3, "d", 4).Wrap(New("cause")).WithStack()
+	err.Free()
+
+	if len(err.Context()) != 0 || err.Unwrap() != nil || err.Error() != "" || len(err.Stack()) != 0 {
+		t.Errorf("Free() without pooling left data: context %d keys, cause %v, message %q",
+			len(err.Context()), err.Unwrap(), err.Error())
+	}
+	err.Free() // Safe to call twice
+}
+
+// TestErrorStripStack verifies that StripStack drops the stack in place and
+// that the error stays usable afterwards.
+func TestErrorStripStack(t *testing.T) {
+	err := New("cached").WithStack()
+	if len(err.Stack()) == 0 {
+		t.Fatal("WithStack() should capture a stack")
+	}
+	if got := err.StripStack(); got != err {
+		t.Error("StripStack() should return the same error")
+	}
+	if err.Stack() != nil || err.stack != nil {
+		t.Errorf("Stack() after StripStack() = %v, want nil", err.Stack())
+	}
+	err.StripStack() // No buffer left; must not touch the pool again
+
+	if len(err.WithStack().Stack()) == 0 {
+		t.Error("WithStack() after StripStack() should capture a new stack")
+	}
+	err.Free()
+
+	remote := New("remote").SetStackStrings([]string{"main.go:1"}).StripStack()
+	if remote.Stack() != nil {
+		t.Errorf("StripStack() should drop stack strings, got %v", remote.Stack())
+	}
+	remote.Free()
+}
+
+// TestErrorScrub verifies that Scrub redacts PII on a copy of the error.
+func TestErrorScrub(t *testing.T) {
+	db := errors.New("no row for ops@example.com")
+	orig := New("login failed for alice@example.com").
+		With("card", "4111 1111 1111 1111", "ssn", "123-45-6789", "attempts", 3).
+		Wrap(New("lookup failed").Wrap(db))
+	defer orig.Free()
+
+	scrubbed := orig.Scrub()
+	defer scrubbed.Free()
+
+	want := "login failed for [REDACTED]: lookup failed: no row for [REDACTED]"
+	if scrubbed.Error() != want {
+		t.Errorf("Scrub().Error() = %q, want %q", scrubbed.Error(), want)
+	}
+	ctx := scrubbed.Context()
+	if ctx["card"] != "[REDACTED]" || ctx["ssn"] != "[REDACTED]" || ctx["attempts"] != 3 {
+		t.Errorf("Scrub().Context() = %v", ctx)
+	}
+	if !strings.Contains(orig.Error(), "alice@example.com") || orig.Context()["ssn"] != "123-45-6789" {
+		t.Errorf("Scrub() modified the original: %q %v", orig.Error(), orig.Context())
+	}
+
+	custom := New("token abc123 rejected").Scrub(regexp.MustCompile(`abc\d+`))
+	if custom.Error() != "token [REDACTED] rejected" {
+		t.Errorf("Scrub(custom) = %q", custom.Error())
+	}
+
+	a, b := New("a@example.com"), New("b")
+	a.Wrap(b)
+	b.Wrap(a)
+	if a.Scrub() == nil {
+		t.Error("Scrub() should stop on a cycle")
+	}
+}
+
+// TestErrorFreeze verifies that modifiers on a frozen error return a modified
+// copy and leave the original unchanged.
+func TestErrorFreeze(t *testing.T) {
+	frozen := New("not found").WithName("NotFound").WithCode(404).With("scope", "users").Freeze()
+	if !frozen.IsFrozen() || New("x").IsFrozen() {
+		t.Fatal("IsFrozen() should report only frozen errors")
+	}
+
+	withKey := frozen.With("k", "v")
+	if withKey == frozen || withKey.IsFrozen() {
+		t.Fatal("With() on a frozen error should return a new, unfrozen error")
 	}
-	if stdTarget != stdErr {
-		t.Errorf("As() unwrapped to wrong error, got %v, want %v", stdTarget, stdErr)
+	if withKey.Context()["k"] != "v" || withKey.Context()["scope"] != "users" || withKey.Code() != 404 {
+		t.Errorf("copy = %v (code %d), want original data plus k=v", withKey.Context(), withKey.Code())
 	}
-}
 
-// TestErrorCount verifies that Count tracks per-instance error occurrences.
-func TestErrorCount(t *testing.T) {
-	err := New("unnamed")
-	defer err.Free()
-	if err.Count() != 0 {
-		t.Errorf("Count() on new error should be 0, got %d", err.Count())
+	cause := New("db down")
+	if w := frozen.Wrap(cause); w == frozen || w.Unwrap() != cause {
+		t.Error("Wrap() on a frozen error should return a wrapping copy")
+	}
+	if c := frozen.WithCode(500); c == frozen || c.Code() != 500 {
+		t.Error("WithCode() on a frozen error should return a modified copy")
+	}
+	if s := frozen.WithStack(); s == frozen || len(s.Stack()) == 0 {
+		t.Error("WithStack() on a frozen error should return a copy with a stack")
+	}
+	frozen.Free()
+	frozen.Reset()
+	var decoded = New("x").Freeze()
+	if err := decoded.UnmarshalBinary([]byte{binaryVersion, 0}); err == nil {
+		t.Error("UnmarshalBinary() into a frozen error should fail")
 	}
 
-	err = Named("test_count").Increment()
-	if err.Count() != 1 {
-		t.Errorf("Count() after Increment() should be 1, got %d", err.Count())
+	if ctx := frozen.Context(); len(ctx) != 1 || ctx["scope"] != "users" || frozen.Code() != 404 ||
+		frozen.Unwrap() != nil || frozen.Name() != "NotFound" || len(frozen.Stack()) != 0 {
+		t.Errorf("frozen error was modified: %v (code %d, cause %v)", ctx, frozen.Code(), frozen.Unwrap())
 	}
 }
 
-// TestErrorCode ensures that Code correctly sets and retrieves HTTP status codes.
-func TestErrorCode(t *testing.T) {
-	err := New("unnamed")
-	defer err.Free()
-	if err.Code() != 0 {
-		t.Errorf("Code() on new error should be 0, got %d", err.Code())
+// TestErrorFreezeConcurrentContext verifies that a frozen error can be read from
+// several goroutines at once and that its Context cannot be changed through the
+// returned map. Run with -race.
+func TestErrorFreezeConcurrentContext(t *testing.T) {
+	frozen := New("x").With("k", 1).Freeze()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ctx := frozen.Context(); ctx["k"] != 1 {
+				t.Errorf("Context() = %v, want k=1", ctx)
+			}
+		}()
 	}
+	wg.Wait()
 
-	err = Named("test_code").WithCode(400)
-	if err.Code() != 400 {
-		t.Errorf("Code() after WithCode(400) should be 400, got %d", err.Code())
+	frozen.Context()["k"] = 2
+	if got := frozen.Context()["k"]; got != 1 {
+		t.Errorf("Context() of a frozen error changed through the returned map: k=%v", got)
 	}
 }
 
-// TestErrorMarshalJSON verifies that JSON serialization includes all expected
-// fields: message, context, cause, code, and stack (when present).
-func TestErrorMarshalJSON(t *testing.T) {
-	// Test basic error with context, code, and cause.
-	err := New("test").
-		With("key", "value").
-		WithCode(400).
-		Wrap(Named("cause"))
-	defer err.Free()
-	data, e := json.Marshal(err)
-	if e != nil {
-		t.Fatalf("MarshalJSON() failed: %v", e)
+// TestErrorMaxWrapDepth verifies that every cause setter stops growing a
+// cause chain beyond Config.MaxWrapDepth.
+func TestErrorMaxWrapDepth(t *testing.T) {
+	originalConfig := currentConfig
+	defer func() { currentConfig = originalConfig }()
+	Configure(Config{MaxWrapDepth: 5})
+
+	var err error = New("root")
+	for i := 0; i < 20; i++ {
+		err = New(fmt.Sprintf("retry %d", i)).Wrap(err)
+		if d := err.(*Error).Depth(); d > 5 {
+			t.Fatalf("Depth() after %d wraps = %d, want at most 5", i+1, d)
+		}
 	}
 
-	want := map[string]interface{}{
-		"message": "test",
-		"context": map[string]interface{}{"key": "value"},
-		"cause":   map[string]interface{}{"name": "cause"},
-		"code":    float64(400),
+	deep := New("a").Wrap(New("b").Wrap(New("c").Wrap(New("d").Wrap(New("e")))))
+	if got := New("top").WrapNotNil(deep); got.Unwrap() != nil {
+		t.Errorf("WrapNotNil() beyond the limit should drop the cause, got %v", got.Unwrap())
 	}
-	var got map[string]interface{}
-	if err := json.Unmarshal(data, &got); err != nil {
-		t.Fatalf("Unmarshal failed: %v", err)
+	if got := New("top").Wrap(deep.Unwrap()); got.Depth() != 5 {
+		t.Errorf("Wrap() up to the limit should keep the cause, got depth %d", got.Depth())
 	}
-
-	if got["message"] != want["message"] {
-		t.Errorf("MarshalJSON() message = %v, want %v", got["message"], want["message"])
+	if got := New("top").Wrapf(deep, "read %s", "config"); got.Unwrap() != nil || got.Error() != "read config" {
+		t.Errorf("Wrapf() beyond the limit should drop the cause, got %q (cause %v)", got.Error(), got.Unwrap())
 	}
-	if !reflect.DeepEqual(got["context"], want["context"]) {
-		t.Errorf("MarshalJSON() context = %v, want %v", got["context"], want["context"])
+	if got := New("top").Wrap(New("old")).ReplaceCause(deep); got.Unwrap() != nil {
+		t.Errorf("ReplaceCause() beyond the limit should remove the cause, got %v", got.Unwrap())
 	}
-	if cause, ok := got["cause"].(map[string]interface{}); !ok || cause["name"] != "cause" {
-		t.Errorf("MarshalJSON() cause = %v, want %v", got["cause"], want["cause"])
+	if got := New("top").WrapMany(deep, New("other")); got.Unwrap() == nil {
+		t.Error("WrapMany() should count its *MultiError as one level")
 	}
-	if code, ok := got["code"].(float64); !ok || code != 400 {
-		t.Errorf("MarshalJSON() code = %v, want %v", got["code"], 400)
+	if got := Wrap(deep, New("top")); got.Unwrap() != nil {
+		t.Errorf("package Wrap() beyond the limit should drop the cause, got %v", got.Unwrap())
+	}
+	if got := Wrapf(deep, "top"); got.Unwrap() != nil {
+		t.Errorf("package Wrapf() beyond the limit should drop the cause, got %v", got.Unwrap())
 	}
 
-	// Test error with stack trace.
-	t.Run("WithStack", func(t *testing.T) {
-		err := New("test").WithStack().WithCode(500)
-		defer err.Free()
-		data, e := json.Marshal(err)
-		if e != nil {
-			t.Fatalf("MarshalJSON() failed: %v", e)
-		}
-		var got map[string]interface{}
-		if err := json.Unmarshal(data, &got); err != nil {
-			t.Fatalf("Unmarshal failed: %v", err)
-		}
-		if _, ok := got["stack"].([]interface{}); !ok || len(got["stack"].([]interface{})) == 0 {
-			t.Error("MarshalJSON() should include non-empty stack")
-		}
-		if code, ok := got["code"].(float64); !ok || code != 500 {
-			t.Errorf("MarshalJSON() code = %v, want 500", got["code"])
-		}
-	})
+	if ConfigureWithValidation(Config{MaxWrapDepth: -1}) == nil {
+		t.Error("ConfigureWithValidation() should reject a negative MaxWrapDepth")
+	}
 }
 
-// TestErrorEdgeCases verifies behavior for unusual inputs, such as nil errors,
-// empty names, and standard library error wrapping.
-func TestErrorEdgeCases(t *testing.T) {
-	// Test nil error handling.
-	var nilErr *Error
-	if nilErr.Is(nil) {
-		t.Errorf("nil.Is(nil) should be false, got true")
+// TestErrorBecause verifies that Because behaves exactly like Wrap.
+func TestErrorBecause(t *testing.T) {
+	db := errors.New("db down")
+	because := New("login failed").Because(db)
+	wrapped := New("login failed").Wrap(db)
+	if because.Error() != wrapped.Error() || because.Unwrap() != db || !errors.Is(because, db) {
+		t.Errorf("Because() = %q (cause %v), want %q", because.Error(), because.Unwrap(), wrapped.Error())
 	}
-	if Is(nilErr, New("test")) {
-		t.Errorf("Is(nil, non-nil) should be false")
+	if err := New("x"); err.Because(nil) != err || err.Unwrap() != nil {
+		t.Error("Because(nil) should leave the error unchanged")
 	}
-
-	// Test empty name mismatch.
-	err := New("empty name")
-	defer err.Free()
-	if err.Is(Named("")) {
-		t.Errorf("Error with empty name should not match unnamed error")
+	if (*Error)(nil).Because(db) != nil {
+		t.Error("Because() on a nil *Error should return nil")
 	}
 
-	// Test wrapping standard library error.
-	stdErr := errors.New("std error")
-	customErr := New("custom").Wrap(stdErr)
-	defer customErr.Free()
-	if !Is(customErr, stdErr) {
-		t.Errorf("Is() should match stdlib error through wrapping")
+	sentinel := New("not found").Freeze()
+	if got := sentinel.Because(db); got == sentinel || sentinel.Unwrap() != nil {
+		t.Error("Because() should not mutate a frozen error")
 	}
+}
 
-	// Test As with nil error.
-	var nilTarget *Error
-	if As(nilErr, &nilTarget) {
-		t.Errorf("As(nil, &nilTarget) should return false")
-	}
+// TestErrorHideFrames verifies that HideFrames filters frames from one
+// error's stack only.
+func TestErrorHideFrames(t *testing.T) {
+	plain := New("plain").WithStack()
+	hidden := New("hidden").WithStack().HideFrames("testing.", "")
+	defer plain.Free()
+	defer hidden.Free()
 
-	// Additional edge case: Wrapping nil error.
-	t.Run("WrapNil", func(t *testing.T) {
-		err := New("wrapper").Wrap(nil)
-		defer err.Free()
-		if err.Unwrap() != nil {
-			t.Errorf("Wrap(nil) should set cause to nil, got %v", err.Unwrap())
-		}
-		if err.Error() != "wrapper" {
-			t.Errorf("Wrap(nil) should preserve message, got %v, want %v", err.Error(), "wrapper")
+	if !plain.StackContains("testing.tRunner") {
+		t.Fatalf("Expected testing frames in an unfiltered stack, got %v", plain.Stack())
+	}
+	for _, frame := range hidden.Stack() {
+		if strings.HasPrefix(frame, "testing.") {
+			t.Errorf("HideFrames() left frame %q", frame)
 		}
-	})
+	}
+	if !hidden.StackContains("TestErrorHideFrames") {
+		t.Errorf("HideFrames() should keep other frames, got %v", hidden.Stack())
+	}
+	if len(hidden.FastStack()) >= len(plain.FastStack()) {
+		t.Errorf("FastStack() should also hide frames: %d vs %d", len(hidden.FastStack()), len(plain.FastStack()))
+	}
+	if c := hidden.Copy(); c.StackContains("testing.tRunner") {
+		t.Error("Copy() should keep hidden frames")
+	}
 }
 
-// TestErrorRetryWithCallback verifies the retry mechanism, ensuring the callback
-// is invoked correctly and retries exhaust as expected for retryable errors.
-func TestErrorRetryWithCallback(t *testing.T) {
-	// Test retry with multiple attempts.
-	attempts := 0
-	retry := NewRetry(
-		WithMaxAttempts(3),
-		WithDelay(1*time.Millisecond),
-		WithOnRetry(func(attempt int, err error) {
-			attempts++
-		}),
-	)
+// TestErrorCodeChain verifies that CodeChain finds a wrapped error's code.
+func TestErrorCodeChain(t *testing.T) {
+	notFound := Named("NotFound").WithCode(404)
+	outer := New("request failed").Wrap(New("lookup").Wrap(notFound))
+	if outer.Code() != 0 || outer.CodeChain() != 404 {
+		t.Errorf("Code()/CodeChain() = %d/%d, want 0/404", outer.Code(), outer.CodeChain())
+	}
+	if got := New("gateway").WithCode(502).Wrap(notFound).CodeChain(); got != 502 {
+		t.Errorf("CodeChain() = %d, want the outermost code 502", got)
+	}
+	if got := New("plain").Wrap(errors.New("io")).CodeChain(); got != 0 {
+		t.Errorf("CodeChain() without codes = %d, want 0", got)
+	}
+	if got := New("ok").WithCode(0).Wrap(notFound).CodeChain(); got != 0 {
+		t.Errorf("CodeChain() = %d, want the explicit outer code 0", got)
+	}
+	if (*Error)(nil).CodeChain() != 0 {
+		t.Error("CodeChain() on a nil *Error should be 0")
+	}
+}
 
-	err := retry.Execute(func() error {
-		return New("retry me").WithRetryable()
-	})
+// TestErrorWithOp verifies that operations prefix Error() along a chain.
+func TestErrorWithOp(t *testing.T) {
+	refused := errors.New("connection refused")
+	insert := Empty().WithOp("db.Insert").Wrap(refused)
+	create := Empty().WithOp("users.Create").Wrap(insert)
 
-	if attempts != 3 {
-		t.Errorf("Expected 3 retry attempts, got %d", attempts)
+	if got, want := create.Error(), "users.Create: db.Insert: connection refused"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
 	}
-	if err == nil {
-		t.Error("Expected retry to exhaust with error, got nil")
+	if create.Op() != "users.Create" || (*Error)(nil).Op() != "" {
+		t.Errorf("Op() = %q, want users.Create", create.Op())
+	}
+	if got, want := New("insert failed").WithOp("db.Insert").Error(), "db.Insert: insert failed"; got != want {
+		t.Errorf("Error() with a message = %q, want %q", got, want)
+	}
+	if got, want := Newf("read %s: %w", "cfg", refused).WithOp("config.Load").Error(), "config.Load: read cfg: connection refused"; got != want {
+		t.Errorf("Error() of a Newf error = %q, want %q", got, want)
 	}
 
-	// Test zero max attempts, expecting one initial attempt (not a retry).
-	t.Run("ZeroAttempts", func(t *testing.T) {
-		attempts := 0
-		retry := NewRetry(
-			WithMaxAttempts(0),
-			WithOnRetry(func(attempt int, err error) {
-				attempts++
-			}),
-		)
-		err := retry.Execute(func() error {
-			return New("retry me").WithRetryable()
-		})
-		// Expect one attempt, as Execute runs the function once before checking retries.
-		if attempts != 1 {
-			t.Errorf("Expected 1 attempt (initial execution), got %d", attempts)
-		}
-		if err == nil {
-			t.Error("Expected error, got nil")
-		}
-	})
+	data, _ := json.Marshal(create)
+	if !strings.Contains(string(data), `"op":"users.Create"`) || !strings.Contains(string(data), `"op":"db.Insert"`) {
+		t.Errorf("MarshalJSON() should include ops, got %s", data)
+	}
 }
 
-// TestErrorStackPresence confirms stack trace behavior for New and Trace methods.
-func TestErrorStackPresence(t *testing.T) {
-	// New should not capture stack.
-	err := New("test")
-	if len(err.Stack()) != 0 {
-		t.Error("New() should not capture stack")
-	}
+// TestErrorMarshalJSONMultiCause verifies that a *MultiError cause is encoded
+// as an array of its members.
+func TestErrorMarshalJSONMultiCause(t *testing.T) {
+	m := NewMultiError()
+	m.Add(New("disk full").WithCode(507), errors.New("plain failure"))
+	err := New("batch failed").Wrap(m)
 
-	// Trace should capture stack.
-	traced := Trace("test")
-	if len(traced.Stack()) == 0 {
-		t.Error("Trace() should capture stack")
+	data, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		t.Fatalf("MarshalJSON() error: %v", marshalErr)
+	}
+	var got struct {
+		Cause []json.RawMessage `json:"cause"`
+	}
+	if unmarshalErr := json.Unmarshal(data, &got); unmarshalErr != nil {
+		t.Fatalf("cause is not an array: %v (%s)", unmarshalErr, data)
+	}
+	if len(got.Cause) != 2 {
+		t.Fatalf("cause has %d members, want 2: %s", len(got.Cause), data)
+	}
+	var first struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	}
+	if json.Unmarshal(got.Cause[0], &first) != nil || first.Message != "disk full" || first.Code != 507 {
+		t.Errorf("first member = %s, want the disk full error object", got.Cause[0])
+	}
+	if string(got.Cause[1]) != `"plain failure"` {
+		t.Errorf("second member = %s, want its message", got.Cause[1])
 	}
 }
 
-// TestErrorStackDepth ensures that stack traces respect the configured maximum depth.
-func TestErrorStackDepth(t *testing.T) {
-	err := Trace("test")
-	frames := err.Stack()
-	if len(frames) > currentConfig.stackDepth {
-		t.Errorf("Stack depth %d exceeds configured max %d", len(frames), currentConfig.stackDepth)
+// TestErrorSetStackStrings verifies that externally supplied frames are
+// returned by Stack, FastStack, and JSON, and survive Copy.
+func TestErrorSetStackStrings(t *testing.T) {
+	frames := []string{
+		"main.handler /srv/app/main.go:42",
+		"main.main /srv/app/main.go:10",
 	}
-}
+	err := New("remote failure").SetStackStrings(frames)
+	defer err.Free()
 
-// TestErrorTransform verifies Transform behavior for nil, non-*Error, and *Error inputs.
-func TestErrorTransform(t *testing.T) {
-	// Test nil input.
-	t.Run("NilError", func(t *testing.T) {
-		result := Transform(nil, func(e *Error) {})
-		if result != nil {
-			t.Error("Should return nil for nil input")
-		}
-	})
+	if !reflect.DeepEqual(err.Stack(), frames) {
+		t.Errorf("Stack() = %v, want %v", err.Stack(), frames)
+	}
+	if !reflect.DeepEqual(err.FastStack(), frames) {
+		t.Errorf("FastStack() = %v, want %v", err.FastStack(), frames)
+	}
+	frames[0] = "mutated"
+	if err.Stack()[0] == "mutated" {
+		t.Error("SetStackStrings() should copy the provided slice")
+	}
 
-	// Test standard library error.
-	t.Run("NonErrorType", func(t *testing.T) {
-		stdErr := errors.New("standard")
-		transformed := Transform(stdErr, func(e *Error) {})
-		if transformed == nil {
-			t.Error("Should not return nil for non-nil input")
-		}
-		if transformed.Error() != "standard" {
-			t.Errorf("Should preserve original message, got %q, want %q", transformed.Error(), "standard")
-		}
-		if transformed == stdErr {
-			t.Error("Should return a new *Error, not the original")
-		}
-	})
+	copied := err.Copy()
+	defer copied.Free()
+	if len(copied.Stack()) != 2 {
+		t.Errorf("Copy() should preserve stack strings, got %v", copied.Stack())
+	}
 
-	// Test transforming *Error.
-	t.Run("TransformError", func(t *testing.T) {
-		orig := New("original")
-		defer orig.Free()
-		transformed := Transform(orig, func(e *Error) {
-			e.With("key", "value")
-		})
-		defer transformed.Free()
+	data, _ := json.Marshal(err)
+	if !strings.Contains(string(data), "/srv/app/main.go:42") {
+		t.Errorf("MarshalJSON() missing stack strings: %s", data)
+	}
 
-		if transformed == orig {
-			t.Error("Should return a copy, not the original")
-		}
-		if transformed.Error() != "original" {
-			t.Errorf("Should preserve original message, got %q, want %q", transformed.Error(), "original")
-		}
-		if transformed.Context()["key"] != "value" {
-			t.Error("Should apply transformations, context missing 'key'='value'")
-		}
-	})
+	err.SetStackStrings(nil)
+	if err.Stack() != nil {
+		t.Errorf("SetStackStrings(nil) should clear frames, got %v", err.Stack())
+	}
 }
 
 // TestErrorWalk ensures Walk traverses the error chain correctly, visiting all errors.
@@ -817,6 +2419,32 @@ func TestErrorFromContext(t *testing.T) {
 	})
 }
 
+// TestErrorFromContextKeys ensures FromContextKeys copies the requested
+// context values into the error's context.
+func TestErrorFromContextKeys(t *testing.T) {
+	type ctxKey string
+	ctx := context.WithValue(context.Background(), ctxKey("trace_id"), "abc123")
+	ctx = context.WithValue(ctx, ctxKey("user_id"), 42)
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	err := FromContextKeys(ctx, errors.New("query failed"), ctxKey("trace_id"), ctxKey("user_id"), ctxKey("missing"))
+	defer err.Free()
+	ctxMap := err.Context()
+	if ctxMap["trace_id"] != "abc123" || ctxMap["user_id"] != 42 {
+		t.Errorf("Expected context values in error context, got %v", ctxMap)
+	}
+	if _, ok := ctxMap["missing"]; ok {
+		t.Error("Expected keys without a value to be skipped")
+	}
+	if !HasContextKey(err, "cancelled") {
+		t.Error("Expected FromContext information to be kept")
+	}
+	if FromContextKeys(ctx, nil, ctxKey("trace_id")) != nil {
+		t.Error("Expected nil for nil input error")
+	}
+}
+
 // TestContextStorage verifies the smallContext optimization and its expansion
 // to a full map, including thread-safety under concurrent access.
 func TestContextStorage(t *testing.T) {
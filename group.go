@@ -27,6 +27,7 @@ type Group struct {
 	ctx           context.Context
 	cancel        context.CancelFunc
 	cancelOnFirst bool
+	sem           chan struct{} // Bounds concurrent goroutines; nil means unlimited
 }
 
 // GroupOption configures a Group.
@@ -51,6 +52,19 @@ func GroupWithLimit(n int) GroupOption {
 	}
 }
 
+// GroupWithConcurrency bounds the number of goroutines running at once.
+// Once n goroutines are active, Go and GoCtx block until one finishes,
+// mirroring errgroup.SetLimit. A value <= 0 means no limit.
+func GroupWithConcurrency(n int) GroupOption {
+	return func(g *Group) {
+		if n <= 0 {
+			g.sem = nil
+			return
+		}
+		g.sem = make(chan struct{}, n)
+	}
+}
+
 // NewGroup creates a Group with the given options applied.
 func NewGroup(opts ...GroupOption) *Group {
 	g := &Group{
@@ -68,10 +82,13 @@ func NewGroup(opts ...GroupOption) *Group {
 // Go starts fn in a new goroutine. Errors returned by fn are collected;
 // nil returns are ignored. Thread-safe: MultiError.Add handles its own locking.
 // cancelOnFirst is read-only after construction so no lock is needed.
+// Blocks while the concurrency limit set by GroupWithConcurrency is reached.
 func (g *Group) Go(fn func() error) {
+	g.acquire()
 	g.wg.Add(1)
 	go func() {
 		defer g.wg.Done()
+		defer g.release()
 		if err := fn(); err != nil {
 			g.errs.Add(err) // MultiError.Add is internally mutex-protected
 			if g.cancelOnFirst && g.cancel != nil {
@@ -84,10 +101,13 @@ func (g *Group) Go(fn func() error) {
 // GoCtx starts fn in a new goroutine, passing the group's context.
 // If the group was created with GroupWithContext, fn receives a context
 // that is cancelled when cancelOnFirst triggers or the parent is done.
+// Blocks while the concurrency limit set by GroupWithConcurrency is reached.
 func (g *Group) GoCtx(fn func(ctx context.Context) error) {
+	g.acquire()
 	g.wg.Add(1)
 	go func() {
 		defer g.wg.Done()
+		defer g.release()
 		if err := fn(g.ctx); err != nil {
 			g.errs.Add(err) // MultiError.Add is internally mutex-protected
 			if g.cancelOnFirst && g.cancel != nil {
@@ -97,6 +117,21 @@ func (g *Group) GoCtx(fn func(ctx context.Context) error) {
 	}()
 }
 
+// acquire reserves a concurrency slot, blocking if the limit is reached.
+// No-op when no limit is configured.
+func (g *Group) acquire() {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+}
+
+// release frees a slot reserved by acquire.
+func (g *Group) release() {
+	if g.sem != nil {
+		<-g.sem
+	}
+}
+
 // Wait blocks until all goroutines have finished and returns a *MultiError
 // containing every error collected, or nil if all succeeded.
 // Always returns *MultiError (never collapses to a raw error) so callers
@@ -191,3 +191,64 @@ func TestGroupConcurrentSafety(t *testing.T) {
 		t.Errorf("expected 50 errors, got %d", multi.Count())
 	}
 }
+
+func TestGroupWithConcurrency(t *testing.T) {
+	const limit = 3
+	g := NewGroup(GroupWithConcurrency(limit))
+
+	var active, peak atomic.Int32
+	for i := 0; i < 20; i++ {
+		i := i
+		g.Go(func() error {
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			active.Add(-1)
+			return fmt.Errorf("error %d", i)
+		})
+	}
+	err := g.Wait()
+
+	if p := peak.Load(); p > limit {
+		t.Errorf("expected at most %d concurrent goroutines, observed %d", limit, p)
+	}
+	multi, ok := err.(*MultiError)
+	if !ok {
+		t.Fatalf("expected *MultiError, got %T", err)
+	}
+	if multi.Count() != 20 {
+		t.Errorf("expected all 20 errors collected, got %d", multi.Count())
+	}
+}
+
+func TestGroupWithConcurrencyCancelOnFirst(t *testing.T) {
+	g := NewGroup(
+		GroupWithContext(context.Background(), true),
+		GroupWithConcurrency(1),
+	)
+	g.GoCtx(func(ctx context.Context) error {
+		return fmt.Errorf("first failure")
+	})
+	// With a limit of 1 this goroutine starts only after the first has
+	// finished and cancelled the shared context.
+	g.GoCtx(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return fmt.Errorf("context was not cancelled")
+		}
+	})
+	err := g.Wait()
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	if !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("expected second goroutine to observe cancellation, got %q", err.Error())
+	}
+}
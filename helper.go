@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"runtime"
 	"strings"
 	"time"
 )
@@ -34,6 +36,20 @@ func Code(err error) int {
 	return DefaultCode
 }
 
+// CodeChain returns the code of the first *Error set with WithCode in err's
+// chain, including *Errors wrapped by other error types, or 0 if none has one,
+// matching the CodeChain method. Unlike Code, a plain error yields 0.
+func CodeChain(err error) int {
+	found := Find(err, func(e error) bool {
+		ee, ok := e.(*Error)
+		return ok && ee.hasCode
+	})
+	if found != nil {
+		return found.(*Error).Code()
+	}
+	return 0
+}
+
 // Context extracts the context map from an error, if it is an *Error.
 // Returns nil for non-*Error types or if no context is present.
 func Context(err error) map[string]interface{} {
@@ -88,25 +104,44 @@ func Count(err error) uint64 {
 	return 0
 }
 
+// ExitCode returns the process exit code set by WithExitCode, walking the chain
+// and returning the first one found. Returns 0 for nil and 1 if no code is set.
+// Example:
+//
+//	if err := run(); err != nil {
+//	  os.Exit(errors.ExitCode(err))
+//	}
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	code := 1
+	Find(err, func(e error) bool {
+		if ee, ok := e.(*Error); ok {
+			if v, ok := ee.contextValue(ctxExitCode); ok {
+				if c, ok := v.(int); ok {
+					code = c
+					return true
+				}
+			}
+		}
+		return false
+	})
+	return code
+}
+
 // Find searches the error chain for the first error matching pred.
 // Returns nil if no match is found or pred is nil; traverses both Unwrap() and Cause() chains.
 func Find(err error, pred func(error) bool) error {
-	for current := err; current != nil; {
-		if pred(current) {
-			return current
+	var found error
+	WalkUntil(err, func(e error) bool {
+		if pred(e) {
+			found = e
+			return false
 		}
-
-		// Attempt to unwrap using Unwrap() or Cause()
-		switch v := current.(type) {
-		case interface{ Unwrap() error }:
-			current = v.Unwrap()
-		case interface{ Cause() error }:
-			current = v.Cause()
-		default:
-			return nil
-		}
-	}
-	return nil
+		return true
+	})
+	return found
 }
 
 // From transforms any error into an *Error, preserving its message and wrapping it if needed.
@@ -139,6 +174,26 @@ func FromContext(ctx context.Context, err error) *Error {
 	return e
 }
 
+// FromContextKeys is FromContext plus the values of the given context keys,
+// stored in the error’s context under fmt.Sprint(key); keys without a value are
+// skipped. Use it to keep request-scoped metadata such as trace or user IDs.
+// Returns nil if input error is nil.
+// Example:
+//
+//	err := errors.FromContextKeys(ctx, dbErr, traceIDKey, userIDKey)
+func FromContextKeys(ctx context.Context, err error, keys ...interface{}) *Error {
+	e := FromContext(ctx, err)
+	if e == nil {
+		return nil
+	}
+	for _, key := range keys {
+		if v := ctx.Value(key); v != nil {
+			e.With(fmt.Sprint(key), v)
+		}
+	}
+	return e
+}
+
 // Category returns the category of an error, if it is an *Error.
 // Returns an empty string for non-*Error types or unset categories.
 func Category(err error) string {
@@ -148,6 +203,25 @@ func Category(err error) string {
 	return ""
 }
 
+// HasCategory reports whether err or any *Error in its chain has category cat.
+func HasCategory(err error, cat ErrorCategory) bool {
+	return Find(err, func(e error) bool {
+		ee, ok := e.(*Error)
+		return ok && ee.IsCategory(cat)
+	}) != nil
+}
+
+// HasTag reports whether err or any *Error in its chain carries tag.
+// Example:
+//
+//	payments := m.Filter(func(err error) bool { return errors.HasTag(err, "payment") })
+func HasTag(err error, tag string) bool {
+	return Find(err, func(e error) bool {
+		ee, ok := e.(*Error)
+		return ok && ee.HasTag(tag)
+	}) != nil
+}
+
 // Has checks if an error contains meaningful content.
 // Returns true for non-nil standard errors or *Error with content (msg, name, template, or cause).
 func Has(err error) bool {
@@ -185,6 +259,16 @@ func Is(err, target error) bool {
 	return errors.Is(err, target)
 }
 
+// IsClientError reports whether err or any *Error in its chain has a 4xx code.
+// Example:
+//
+//	if errors.IsClientError(err) {
+//	  clientErrors.Inc() // The caller's fault; don't page anyone
+//	}
+func IsClientError(err error) bool {
+	return hasCodeClass(err, 4)
+}
+
 // IsError checks if an error is an instance of *Error.
 // Returns true only for this package's custom error type; false for nil or other types.
 func IsError(err error) bool {
@@ -217,7 +301,9 @@ func IsNull(err error) bool {
 }
 
 // IsRetryable checks if an error is retryable.
-// For *Error, checks context for retry flag; for others, looks for "retry" or timeout in message.
+// For *Error, checks context for the WithRetryable flag, then its cause; other errors
+// are retryable if IsTimeout reports true, or with Config.HeuristicClassification
+// if their message contains "retry".
 // Returns false for nil errors; thread-safe for *Error types.
 func IsRetryable(err error) bool {
 	if err == nil {
@@ -245,23 +331,67 @@ func IsRetryable(err error) bool {
 			return IsRetryable(e.cause)
 		}
 	}
-	lowerMsg := strings.ToLower(err.Error())
-	return IsTimeout(err) || strings.Contains(lowerMsg, "retry")
+	if IsTimeout(err) {
+		return true
+	}
+	return currentConfig.heuristics && strings.Contains(strings.ToLower(err.Error()), "retry")
+}
+
+// IsSentinel reports whether err or any *Error in its chain matches sentinel by
+// name, code, and category together, the identity of registry-defined errors.
+// Unlike Is, it ignores the message, so an instance built from a template (with
+// its own formatted text) matches the sentinel it was defined from, while an
+// error sharing only the name does not. Returns false if sentinel is nil or unnamed.
+// Example:
+//
+//	var ErrNotFound = errors.Named("ErrNotFound").WithCode(404).WithCategory("store")
+//
+//	err := errors.Newf("user %d not found", id).WithName("ErrNotFound").WithCode(404).WithCategory("store")
+//	errors.IsSentinel(fmt.Errorf("lookup: %w", err), ErrNotFound) // true
+func IsSentinel(err error, sentinel *Error) bool {
+	if sentinel == nil || sentinel.name == "" {
+		return false
+	}
+	return Find(err, func(e error) bool {
+		ee, ok := e.(*Error)
+		return ok && ee.name == sentinel.name && ee.code == sentinel.code && ee.category == sentinel.category
+	}) != nil
+}
+
+// IsServerError reports whether err or any *Error in its chain has a 5xx code.
+func IsServerError(err error) bool {
+	return hasCodeClass(err, 5)
+}
+
+// hasCodeClass walks the chain of err looking for an *Error whose code is in class.
+func hasCodeClass(err error, class int) bool {
+	return Find(err, func(e error) bool {
+		ee, ok := e.(*Error)
+		return ok && ee.CodeClass() == class
+	}) != nil
 }
 
 // IsTimeout checks if an error indicates a timeout.
-// For *Error, checks context for timeout flag; for others, looks for "timeout" in message.
-// Returns false for nil errors.
+// Walks the chain: an *Error's WithTimeout flag decides, and other errors count if
+// they implement Timeout() bool returning true (e.g. context.DeadlineExceeded,
+// net.Error). With Config.HeuristicClassification, a message containing
+// "timeout" also counts. Returns false for nil errors.
 func IsTimeout(err error) bool {
 	if err == nil {
 		return false
 	}
-	if e, ok := err.(*Error); ok {
-		if val, ok := e.Context()[ctxTimeout].(bool); ok {
-			return val
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		if e, ok := current.(*Error); ok {
+			if v, ok := e.contextValue(ctxTimeout); ok {
+				if val, ok := v.(bool); ok {
+					return val
+				}
+			}
+		} else if t, ok := current.(interface{ Timeout() bool }); ok && t.Timeout() {
+			return true
 		}
 	}
-	return strings.Contains(strings.ToLower(err.Error()), "timeout")
+	return currentConfig.heuristics && strings.Contains(strings.ToLower(err.Error()), "timeout")
 }
 
 // Merge combines multiple errors into a single *Error.
@@ -299,6 +429,18 @@ func Merge(errs ...error) *Error {
 	return combined
 }
 
+// Must returns e, panicking if it is nil or empty (see IsEmpty).
+// Intended for package-level error definitions, so misconfiguration fails fast at startup.
+// Example:
+//
+//	var ErrQuota = errors.Must(errors.New("quota exceeded").WithCode(429))
+func Must(e *Error) *Error {
+	if e.IsEmpty() {
+		panic("errors.Must: nil or empty error")
+	}
+	return e
+}
+
 // Name returns the name of an error, if it is an *Error.
 // Returns an empty string for non-*Error types or unset names.
 func Name(err error) string {
@@ -308,6 +450,142 @@ func Name(err error) string {
 	return ""
 }
 
+// Ops returns the operations set by WithOp along err's chain, outermost
+// first, skipping errors without one. Returns nil if there are none.
+// Example:
+//
+//	errors.Ops(err) // ["users.Create", "db.Insert"]
+func Ops(err error) []string {
+	var ops []string
+	WalkUntil(err, func(e error) bool {
+		if ee, ok := e.(*Error); ok && ee.op != "" {
+			ops = append(ops, ee.op)
+		}
+		return true
+	})
+	return ops
+}
+
+// Recover converts a value returned by recover() into an *Error with the panic
+// message, category "system", code 500, and a stack trace starting at the panic
+// site. If the value is an error it becomes the cause. Returns nil if recovered
+// is nil. Call it directly from the deferred function that calls recover().
+// Example:
+//
+//	defer func() {
+//	  if err := errors.Recover(recover()); err != nil {
+//	    log.Println(err.Format())
+//	  }
+//	}()
+func Recover(recovered interface{}) *Error {
+	if recovered == nil {
+		return nil
+	}
+	var e *Error
+	if cause, ok := recovered.(error); ok {
+		e = New("panic").Wrap(cause)
+	} else {
+		e = New(fmt.Sprintf("panic: %v", recovered))
+	}
+	e.stack = trimPanicFrames(captureStack(1))
+	return e.WithCategory("system").WithCode(500)
+}
+
+// trimPanicFrames drops the frames above a panic site: the recovering deferred
+// function, runtime.gopanic, and any runtime frames that raised the panic (such
+// as runtime.sigpanic). Returns pcs unchanged if no panic frame is present.
+func trimPanicFrames(pcs []uintptr) []uintptr {
+	start := -1
+	for i, pc := range pcs {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil {
+			continue
+		}
+		name := fn.Name()
+		if name == "runtime.gopanic" {
+			start = i + 1
+		} else if start == i && strings.HasPrefix(name, "runtime.") {
+			start = i + 1
+		}
+	}
+	if start <= 0 || start >= len(pcs) {
+		return pcs
+	}
+	// Shift in place so a pooled buffer keeps its capacity; see captureStack.
+	n := copy(pcs, pcs[start:])
+	return pcs[:n]
+}
+
+// RequestID returns the request correlation ID set by WithRequestID.
+// Walks the chain and returns the first ID found; empty string if none is set.
+func RequestID(err error) string {
+	var id string
+	Find(err, func(e error) bool {
+		if ee, ok := e.(*Error); ok {
+			if v, ok := ee.contextValue(ctxRequestID); ok {
+				id, ok = v.(string)
+				return ok
+			}
+		}
+		return false
+	})
+	return id
+}
+
+// Root returns the deepest error in err's chain, following Unwrap() and Cause(),
+// or err itself if it wraps nothing. A chain that loops back to an *Error
+// already visited stops there. Returns nil if err is nil.
+// Example:
+//
+//	log.Println("root cause:", errors.Root(err))
+func Root(err error) error {
+	var seen map[*Error]struct{}
+	for err != nil {
+		if e, ok := err.(*Error); ok {
+			if seen == nil {
+				seen = make(map[*Error]struct{})
+			}
+			if _, dup := seen[e]; dup {
+				return err
+			}
+			seen[e] = struct{}{}
+		}
+		var next error
+		switch v := err.(type) {
+		case interface{ Unwrap() error }:
+			next = v.Unwrap()
+		case interface{ Cause() error }:
+			next = v.Cause()
+		}
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+	return nil
+}
+
+// SameRoot reports whether a and b share a root cause: their Root errors are
+// the same error, or have the same message. Useful to collapse differently
+// wrapped failures, such as "payment failed: db down" and "notify failed:
+// db down", into one incident. Returns false if either is nil.
+// Example:
+//
+//	if errors.SameRoot(payErr, notifyErr) {
+//	  incident.Add(notifyErr)
+//	}
+func SameRoot(a, b error) bool {
+	ra, rb := Root(a), Root(b)
+	if ra == nil || rb == nil {
+		return false
+	}
+	// Interface comparison panics on non-comparable dynamic types.
+	if ta, tb := reflect.TypeOf(ra), reflect.TypeOf(rb); ta == tb && ta.Comparable() && ra == rb {
+		return true
+	}
+	return ra.Error() == rb.Error()
+}
+
 // UnwrapAll returns a slice of all errors in the chain, including the root error.
 // Traverses both Unwrap() and Cause() chains; returns nil if err is nil.
 func UnwrapAll(err error) []error {
@@ -369,8 +647,25 @@ func Unwrap(err error) error {
 // Walk traverses the error chain, applying fn to each error.
 // Supports both Unwrap() and Cause() interfaces; stops at nil or non-unwrappable errors.
 func Walk(err error, fn func(error)) {
+	WalkUntil(err, func(e error) bool {
+		fn(e)
+		return true
+	})
+}
+
+// WalkUntil traverses the error chain like Walk, stopping as soon as fn returns false.
+// Supports both Unwrap() and Cause() interfaces; Find is built on it.
+// Example:
+//
+//	errors.WalkUntil(err, func(e error) bool {
+//	  log.Println(e)
+//	  return errors.Code(e) != 404 // Stop at the first 404
+//	})
+func WalkUntil(err error, fn func(error) bool) {
 	for current := err; current != nil; {
-		fn(current)
+		if !fn(current) {
+			return
+		}
 
 		// Attempt to unwrap using Unwrap() or Cause()
 		switch v := current.(type) {
@@ -407,6 +702,7 @@ func WithStack(err error) *Error {
 
 // Wrap creates a new *Error that wraps another error with additional context.
 // Uses a copy of the provided wrapper *Error; returns nil if err is nil.
+// Config.MaxWrapDepth applies as for the Wrap method.
 func Wrap(err error, wrapper *Error) *Error {
 	if err == nil {
 		return nil
@@ -415,19 +711,51 @@ func Wrap(err error, wrapper *Error) *Error {
 		wrapper = newError()
 	}
 	newErr := wrapper.Copy()
-	newErr.cause = err
+	newErr.setCause(err)
 	return newErr
 }
 
 // Wrapf creates a new formatted *Error that wraps another error.
 // Formats the message and sets the cause; returns nil if err is nil.
+// Config.MaxWrapDepth applies as for the Wrap method.
 func Wrapf(err error, format string, args ...interface{}) *Error {
 	if err == nil {
 		return nil
 	}
 	e := newError()
 	e.msg = fmt.Sprintf(format, args...)
-	e.cause = err
+	e.setCause(err)
+	return e
+}
+
+// Wrapped runs fn and, if it fails, returns its error as an *Error named name
+// with a stack trace captured at the caller. An *Error result is copied rather
+// than modified and keeps any stack it already has; other errors are wrapped
+// without changing their message.
+// Returns nil if fn succeeds.
+// Example:
+//
+//	err := errors.Wrapped("LoadConfig", func() error {
+//	  return loadConfig(path)
+//	})
+func Wrapped(name string, fn func() error) error {
+	err := fn()
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if x, ok := err.(*Error); ok {
+		if x == nil {
+			return nil
+		}
+		e = x.Copy()
+	} else {
+		e = Newf("%w", err)
+	}
+	e.name = name
+	if len(e.stack) == 0 && len(e.stackStrings) == 0 {
+		e.stack = captureStack(1)
+	}
 	return e
 }
 
@@ -438,9 +766,10 @@ func Err(msg string, err error) *Error {
 
 // Join returns an error that wraps the given errors.
 // Any nil error values are discarded.
-// Join returns nil if every error is nil.
-// The error formats as the concatenation of the errors' strings, separated by newlines.
-// The resulting error implements Unwrap() []error if multiple non-nil errors are present.
+// Join returns nil if every error is nil, and the error itself if only one is non-nil.
+// Otherwise it returns a *MultiError, which formats like MultiError.Error and
+// implements Unwrap() []error, so errors.Is and errors.As search every member.
+// It cannot return *Error, whose Unwrap() error method reports a single cause.
 func Join(errs ...error) error {
 	nonNil := make([]error, 0, len(errs))
 	for _, err := range errs {
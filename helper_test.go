@@ -1,8 +1,11 @@
 package errors
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"reflect"
 	"runtime"
 	"strings"
 	"sync"
@@ -56,6 +59,55 @@ func TestHelperWarmStackPool(t *testing.T) {
 	}
 }
 
+// TestHelperAutoWarmPool verifies that the pool tuner warms a pool while misses
+// are high and stops once Gets are served from the pool.
+func TestHelperAutoWarmPool(t *testing.T) {
+	testMu.Lock()
+	defer testMu.Unlock()
+
+	originalConfig := currentConfig
+	defer func() { currentConfig = originalConfig }()
+	currentConfig.disablePooling = false
+
+	pool := NewErrorPool()
+	added := 0
+	tuner := newPoolTuner(pool, func(n int) {
+		for i := 0; i < n; i++ {
+			pool.Put(&Error{})
+		}
+		added += n
+	})
+
+	if !tuner.step() || added != 0 {
+		t.Fatalf("Tuner should wait without traffic, added %d", added)
+	}
+
+	// Drive allocations with an empty pool: every Get misses.
+	for i := 0; i < 100; i++ {
+		_ = pool.Get()
+	}
+	if _, misses := pool.Stats(); misses != 100 {
+		t.Fatalf("Expected 100 misses, got %d", misses)
+	}
+	if !tuner.step() {
+		t.Error("Tuner should continue after a high-miss sample")
+	}
+	if added != 100 {
+		t.Errorf("Expected the pool to grow by 100, got %d", added)
+	}
+
+	// Gets are now served from the warmed pool, so tuning stops.
+	for i := 0; i < 50; i++ {
+		_ = pool.Get()
+	}
+	if tuner.step() {
+		t.Error("Tuner should stop once the miss rate is low")
+	}
+	if added != 100 {
+		t.Errorf("Pool should not grow on a low-miss sample, added %d", added)
+	}
+}
+
 // TestHelperCaptureStack verifies that captureStack captures the correct stack frames.
 func TestHelperCaptureStack(t *testing.T) {
 	stack := captureStack(0)
@@ -255,3 +307,411 @@ func TestHelperPackageIsNull(t *testing.T) {
 		})
 	}
 }
+
+// TestHelperRequestID verifies that RequestID finds an ID attached deep in the chain.
+func TestHelperRequestID(t *testing.T) {
+	inner := New("db failure").WithRequestID("req-123")
+	defer inner.Free()
+	outer := New("handler failed").Wrap(Wrapf(inner, "service failed"))
+	defer outer.Free()
+
+	if got := RequestID(outer); got != "req-123" {
+		t.Errorf("RequestID() = %q, want %q", got, "req-123")
+	}
+	if got := RequestID(errors.New("plain")); got != "" {
+		t.Errorf("RequestID() on std error = %q, want empty", got)
+	}
+	if got := RequestID(nil); got != "" {
+		t.Errorf("RequestID(nil) = %q, want empty", got)
+	}
+
+	outer.WithRequestID("req-outer")
+	if got := RequestID(outer); got != "req-outer" {
+		t.Errorf("RequestID() should prefer the outermost ID, got %q", got)
+	}
+}
+
+// TestHelperMust verifies that Must returns valid errors and panics on nil or empty ones.
+func TestHelperMust(t *testing.T) {
+	err := New("x")
+	defer err.Free()
+	if got := Must(err); got != err {
+		t.Errorf("Must() = %v, want the same error", got)
+	}
+
+	for name, e := range map[string]*Error{"empty": New(""), "nil": nil} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Must(%s) should panic", name)
+				}
+			}()
+			Must(e)
+		}()
+	}
+}
+
+// TestHelperExitCode verifies that exit codes round-trip through the chain and default to 1.
+func TestHelperExitCode(t *testing.T) {
+	inner := New("config not found").WithExitCode(78)
+	defer inner.Free()
+	outer := New("startup failed").Wrap(inner)
+	defer outer.Free()
+
+	if got := ExitCode(inner); got != 78 {
+		t.Errorf("ExitCode() = %d, want 78", got)
+	}
+	if got := ExitCode(outer); got != 78 {
+		t.Errorf("ExitCode() through chain = %d, want 78", got)
+	}
+	if got := ExitCode(errors.New("plain")); got != 1 {
+		t.Errorf("ExitCode() on std error = %d, want 1", got)
+	}
+	if got := ExitCode(New("no code").WithCode(404)); got != 1 {
+		t.Errorf("ExitCode() should ignore the HTTP code, got %d", got)
+	}
+	if got := ExitCode(nil); got != 0 {
+		t.Errorf("ExitCode(nil) = %d, want 0", got)
+	}
+}
+
+// TestHelperWalkUntil verifies that traversal stops once fn returns false.
+func TestHelperWalkUntil(t *testing.T) {
+	err := New("first").Wrap(New("second").Wrap(errors.New("third")))
+	defer err.Free()
+
+	var visited []string
+	WalkUntil(err, func(e error) bool {
+		visited = append(visited, e.Error())
+		return len(visited) < 2
+	})
+	if len(visited) != 2 || visited[1] != "second: third" {
+		t.Errorf("Expected to stop after the second error, visited %q", visited)
+	}
+
+	count := 0
+	WalkUntil(err, func(error) bool {
+		count++
+		return true
+	})
+	if count != 3 {
+		t.Errorf("Expected 3 errors when never stopping, got %d", count)
+	}
+
+	WalkUntil(nil, func(error) bool {
+		t.Error("fn should not be called for nil")
+		return true
+	})
+}
+
+// TestHelperJoin verifies that Join drops nils and that errors.Is finds any joined sentinel.
+func TestHelperJoin(t *testing.T) {
+	errA := Const("a", "sentinel a")
+	errB := errors.New("sentinel b")
+
+	joined := Join(nil, New("context").Wrap(errA), errB)
+	if _, ok := joined.(interface{ Unwrap() []error }); !ok {
+		t.Fatalf("Join() = %T, want an error implementing Unwrap() []error", joined)
+	}
+	if !errors.Is(joined, errA) || !errors.Is(joined, errB) {
+		t.Error("errors.Is should find every joined sentinel")
+	}
+	if errors.Is(joined, errors.New("sentinel c")) {
+		t.Error("errors.Is should not match an unrelated error")
+	}
+
+	if Join(nil, nil) != nil {
+		t.Error("Join() of only nils should be nil")
+	}
+	if Join(nil, errB) != errB {
+		t.Error("Join() of a single error should return it unchanged")
+	}
+}
+
+// panicAt panics with v; TestHelperRecover expects it as the top stack frame.
+func panicAt(v interface{}) {
+	panic(v)
+}
+
+// recoverFrom runs fn and returns the error built by Recover from its panic.
+func recoverFrom(fn func()) (err *Error) {
+	defer func() {
+		err = Recover(recover())
+	}()
+	fn()
+	return nil
+}
+
+// TestHelperRecover verifies that a recovered panic becomes a system error
+// whose stack starts at the panic site.
+func TestHelperRecover(t *testing.T) {
+	err := recoverFrom(func() { panicAt("boom") })
+	if err == nil {
+		t.Fatal("Recover() should return an error for a panic")
+	}
+	defer err.Free()
+	if err.Error() != "panic: boom" {
+		t.Errorf("Recover() message = %q, want %q", err.Error(), "panic: boom")
+	}
+	if err.Code() != 500 || err.Category() != "system" {
+		t.Errorf("Recover() code/category = %d/%q, want 500/system", err.Code(), err.Category())
+	}
+	stack := err.Stack()
+	if len(stack) == 0 || !strings.Contains(stack[0], "panicAt") {
+		t.Errorf("Recover() stack should start at the panic site, got %v", stack)
+	}
+
+	cause := errors.New("bad state")
+	wrapped := recoverFrom(func() { panicAt(cause) })
+	defer wrapped.Free()
+	if !errors.Is(wrapped, cause) {
+		t.Error("Recover() should wrap an error value as the cause")
+	}
+
+	var nilMap map[string]int
+	runtimeErr := recoverFrom(func() { nilMap["x"] = 1 })
+	defer runtimeErr.Free()
+	if s := runtimeErr.Stack(); len(s) == 0 || !strings.Contains(s[0], "TestHelperRecover") {
+		t.Errorf("Recover() stack should skip runtime panic frames, got %v", s)
+	}
+
+	if Recover(nil) != nil {
+		t.Error("Recover(nil) should return nil")
+	}
+}
+
+// TestHelperConfigureWithValidation verifies that invalid values are rejected
+// without changing the configuration, and valid ones are applied.
+func TestHelperConfigureWithValidation(t *testing.T) {
+	originalConfig := currentConfig
+	defer func() { currentConfig = originalConfig }()
+
+	before := currentConfig.stackDepth
+	err := ConfigureWithValidation(Config{StackDepth: -5, ContextSize: -1})
+	if err == nil {
+		t.Fatal("ConfigureWithValidation() should reject negative values")
+	}
+	if !strings.Contains(err.Error(), "StackDepth -5") || !strings.Contains(err.Error(), "ContextSize -1") {
+		t.Errorf("ConfigureWithValidation() error should name each invalid field, got %q", err)
+	}
+	if currentConfig.stackDepth != before {
+		t.Errorf("invalid config should not be applied, stackDepth = %d", currentConfig.stackDepth)
+	}
+
+	if err := ConfigureWithValidation(Config{StackDepth: 16, FilterInternal: true}); err != nil {
+		t.Fatalf("ConfigureWithValidation() = %v, want nil", err)
+	}
+	if currentConfig.stackDepth != 16 {
+		t.Errorf("valid config should be applied, stackDepth = %d", currentConfig.stackDepth)
+	}
+}
+
+// TestHelperClassificationHeuristics verifies that IsTimeout and IsRetryable
+// ignore message text unless Config.HeuristicClassification is set.
+func TestHelperClassificationHeuristics(t *testing.T) {
+	originalConfig := currentConfig
+	defer func() { currentConfig = originalConfig }()
+	Configure(Config{})
+
+	noTimeout := errors.New("no timeout configured")
+	badRetry := New("invalid retry policy")
+	defer badRetry.Free()
+	if IsTimeout(noTimeout) || IsRetryable(noTimeout) {
+		t.Error("a message mentioning timeout should not classify by default")
+	}
+	if IsRetryable(badRetry) {
+		t.Error("a message mentioning retry should not be retryable by default")
+	}
+
+	marked := New("request failed").WithTimeout()
+	defer marked.Free()
+	outer := New("handler failed").Wrap(marked)
+	defer outer.Free()
+	if !IsTimeout(outer) || !IsRetryable(outer) {
+		t.Error("an explicit WithTimeout flag in the chain should classify as timeout")
+	}
+	if !IsTimeout(fmt.Errorf("fetch: %w", context.DeadlineExceeded)) {
+		t.Error("errors implementing Timeout() bool should classify as timeout")
+	}
+	if !IsRetryable(New("flaky").WithRetryable()) {
+		t.Error("an explicit WithRetryable flag should be retryable")
+	}
+
+	Configure(Config{HeuristicClassification: true})
+	if !IsTimeout(noTimeout) || !IsRetryable(errors.New("please retry later")) {
+		t.Error("HeuristicClassification should enable message matching")
+	}
+}
+
+// TestHelperIsSentinel verifies that IsSentinel matches templated instances by
+// name, code, and category, regardless of message.
+func TestHelperIsSentinel(t *testing.T) {
+	errNotFound := Named("ErrNotFound").WithCode(404).WithCategory("store")
+	defer errNotFound.Free()
+
+	instance := Newf("user %d not found", 42).WithName("ErrNotFound").WithCode(404).WithCategory("store")
+	defer instance.Free()
+	if !IsSentinel(fmt.Errorf("lookup: %w", instance), errNotFound) {
+		t.Error("IsSentinel() should match a wrapped templated instance")
+	}
+
+	otherCode := New("gone").WithName("ErrNotFound").WithCode(410).WithCategory("store")
+	defer otherCode.Free()
+	if IsSentinel(otherCode, errNotFound) {
+		t.Error("IsSentinel() should not match a different code")
+	}
+	if IsSentinel(New("x").WithName("ErrNotFound").WithCode(404), errNotFound) {
+		t.Error("IsSentinel() should not match a different category")
+	}
+	if IsSentinel(instance, nil) || IsSentinel(New("anon"), New("anon")) {
+		t.Error("IsSentinel() should be false for nil or unnamed sentinels")
+	}
+}
+
+// TestHelperMaxPoolSize verifies that Put discards errors once the pool holds
+// Config.MaxPoolSize instances.
+func TestHelperMaxPoolSize(t *testing.T) {
+	testMu.Lock()
+	defer testMu.Unlock()
+
+	originalConfig := currentConfig
+	defer func() { currentConfig = originalConfig }()
+	Configure(Config{MaxPoolSize: 10})
+
+	pool := NewErrorPool()
+	for i := 0; i < 50; i++ {
+		pool.Put(&Error{})
+	}
+	if got := pool.Size(); got != 10 {
+		t.Errorf("Expected pool size capped at 10, got %d", got)
+	}
+
+	_ = pool.Get()
+	pool.Put(&Error{})
+	if got := pool.Size(); got > 10 {
+		t.Errorf("Expected pool size to stay within 10 after reuse, got %d", got)
+	}
+
+	if err := ConfigureWithValidation(Config{MaxPoolSize: -1}); err == nil {
+		t.Error("Expected negative MaxPoolSize to be rejected")
+	}
+}
+
+// TestHelperCodeClassPredicates verifies IsClientError and IsServerError,
+// including codes found deeper in the chain.
+func TestHelperCodeClassPredicates(t *testing.T) {
+	notFound := New("missing").WithCode(404)
+	unavailable := New("down").WithCode(503)
+	wrapped := fmt.Errorf("handler: %w", New("context").Wrap(unavailable))
+
+	tests := []struct {
+		name           string
+		err            error
+		client, server bool
+	}{
+		{"4xx", notFound, true, false},
+		{"5xx", unavailable, false, true},
+		{"wrapped 5xx", wrapped, false, true},
+		{"no code", New("plain"), false, false},
+		{"std error", errors.New("std"), false, false},
+		{"nil", nil, false, false},
+	}
+	for _, tt := range tests {
+		if got := IsClientError(tt.err); got != tt.client {
+			t.Errorf("IsClientError(%s) = %v, want %v", tt.name, got, tt.client)
+		}
+		if got := IsServerError(tt.err); got != tt.server {
+			t.Errorf("IsServerError(%s) = %v, want %v", tt.name, got, tt.server)
+		}
+	}
+}
+
+// TestHelperSameRoot verifies Root and SameRoot across different wrappers.
+func TestHelperSameRoot(t *testing.T) {
+	dbDown := errors.New("db down")
+	payment := New("payment failed").Wrap(dbDown)
+	notify := fmt.Errorf("notify failed: %w", New("send").Wrap(dbDown))
+
+	if Root(payment) != dbDown || Root(notify) != dbDown {
+		t.Errorf("Root() = %v, %v, want %v", Root(payment), Root(notify), dbDown)
+	}
+	if Root(dbDown) != dbDown || Root(nil) != nil {
+		t.Error("Root() of an unwrapped error should be itself, and nil for nil")
+	}
+	if !SameRoot(payment, notify) {
+		t.Error("SameRoot() should match wrappers over the same root")
+	}
+	if !SameRoot(payment, New("retry failed").Wrap(errors.New("db down"))) {
+		t.Error("SameRoot() should match roots with the same message")
+	}
+	if SameRoot(payment, New("other").Wrap(errors.New("disk full"))) || SameRoot(payment, nil) {
+		t.Error("SameRoot() should not match different roots or nil")
+	}
+
+	a, b := New("a"), New("b")
+	a.Wrap(b)
+	b.Wrap(a)
+	if Root(a) == nil {
+		t.Error("Root() should stop on a cycle")
+	}
+}
+
+// TestHelperWrapped verifies that Wrapped names and traces a failing function's
+// error and passes success through.
+func TestHelperWrapped(t *testing.T) {
+	if err := Wrapped("Noop", func() error { return nil }); err != nil {
+		t.Errorf("Wrapped() of a successful function = %v, want nil", err)
+	}
+
+	io := errors.New("disk full")
+	err := Wrapped("SaveFile", func() error { return io })
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Wrapped() returned %T, want *Error", err)
+	}
+	if e.Name() != "SaveFile" || e.Error() != "disk full" || !errors.Is(err, io) {
+		t.Errorf("Wrapped() = name %q, message %q, want SaveFile wrapping %v", e.Name(), e.Error(), io)
+	}
+	if !e.StackContains("TestHelperWrapped") {
+		t.Errorf("Wrapped() stack should start at the caller, got %v", e.Stack())
+	}
+
+	orig := New("timeout").WithCode(504)
+	err = Wrapped("Fetch", func() error { return orig })
+	if Name(err) != "Fetch" || Code(err) != 504 || orig.Name() != "" {
+		t.Errorf("Wrapped() should name a copy of an *Error, got %q (code %d), original %q", Name(err), Code(err), orig.Name())
+	}
+}
+
+// TestHelperCodeChain verifies that CodeChain searches through any wrapper.
+func TestHelperCodeChain(t *testing.T) {
+	notFound := Named("NotFound").WithCode(404)
+	if got := CodeChain(New("request failed").Wrap(notFound)); got != 404 {
+		t.Errorf("CodeChain() = %d, want 404", got)
+	}
+	if got := CodeChain(fmt.Errorf("handler: %w", notFound)); got != 404 {
+		t.Errorf("CodeChain() through fmt.Errorf = %d, want 404", got)
+	}
+	if got := CodeChain(New("no code")); got != 0 {
+		t.Errorf("CodeChain() without codes = %d, want 0", got)
+	}
+	if got := CodeChain(errors.New("plain")); got != 0 {
+		t.Errorf("CodeChain() of a plain error = %d, want 0 like the method", got)
+	}
+	wrapped := New("ok").WithCode(0).Wrap(notFound)
+	if got, want := CodeChain(wrapped), wrapped.CodeChain(); got != 0 || got != want {
+		t.Errorf("CodeChain() = %d, method = %d, want both to stop at the explicit code 0", got, want)
+	}
+}
+
+// TestHelperOps verifies that Ops collects operations outermost first.
+func TestHelperOps(t *testing.T) {
+	err := fmt.Errorf("handler: %w",
+		Empty().WithOp("users.Create").Wrap(New("no op").Wrap(Empty().WithOp("db.Insert").Wrap(errors.New("refused")))))
+	if got, want := Ops(err), []string{"users.Create", "db.Insert"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ops() = %v, want %v", got, want)
+	}
+	if Ops(errors.New("plain")) != nil || Ops(nil) != nil {
+		t.Error("Ops() without operations should be nil")
+	}
+}
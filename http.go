@@ -115,3 +115,33 @@ func HTTPStatusCode(err error, defaultCode int) int {
 	}
 	return defaultCode
 }
+
+// RecoverHandler wraps next so that a panic in a handler is converted with Recover
+// and written as an HTTP error response instead of aborting the connection.
+// The panic message is not sent to the client unless opts enable a body;
+// http.ErrAbortHandler is re-panicked so net/http can abort the response as usual.
+//
+// Example — log recovered panics and return a JSON body:
+//
+//	mux := errors.RecoverHandler(router,
+//	    errors.WithBodyFunc(func(e error) string {
+//	        log.Println(errors.Stack(e))
+//	        return `{"error":"internal server error"}`
+//	    }),
+//	)
+func RecoverHandler(next http.Handler, opts ...HTTPOption) http.Handler {
+	opts = append([]HTTPOption{WithBody(false)}, opts...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			HTTPError(w, Recover(p), opts...)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -119,3 +119,39 @@ func TestHTTPErrorSentinelError(t *testing.T) {
 		t.Errorf("sentinel: got %d, want %d", w.Code, http.StatusForbidden)
 	}
 }
+
+func TestHTTPRecoverHandler(t *testing.T) {
+	h := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("secret detail")
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want 500", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "secret detail") {
+		t.Errorf("panic message should not be sent by default, got %q", rec.Body.String())
+	}
+
+	var recovered error
+	h = RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), WithBodyFunc(func(e error) string {
+		recovered = e
+		return "oops"
+	}))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Body.String() != "oops" || recovered == nil || recovered.Error() != "panic: boom" {
+		t.Errorf("got body %q and error %v", rec.Body.String(), recovered)
+	}
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("ErrAbortHandler should be re-panicked, got %v", p)
+		}
+	}()
+	RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,7 @@
+// Package errstate gives the subpackages of this module access to unexported
+// state of *errors.Error without adding it to the public API.
+package errstate
+
+// SetCount sets the occurrence count reported by an *errors.Error's Count
+// method; other error types are ignored. Installed by package errors at init.
+var SetCount func(err error, count uint64)
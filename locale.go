@@ -0,0 +1,69 @@
+// Localized message templates keyed by locale and error name.
+//
+// Templates use the {key} placeholders of Error.Render, filled from the
+// error's context, so a message can be re-rendered in any registered locale
+// after the error was created:
+//
+//	errors.RegisterLocaleTemplate("fr", "NotFound", "utilisateur {user} introuvable")
+//	err := errors.Named("NotFound").Msgf("user alice not found").With("user", "alice")
+//	err.RenderLocale("fr-CA", "fr", "en") // "utilisateur alice introuvable"
+
+package errors
+
+import "sync"
+
+// localeTemplates maps locale, then error name, to a template.
+// localeMu guards it against concurrent registration.
+var (
+	localeMu        sync.RWMutex
+	localeTemplates = make(map[string]map[string]string)
+)
+
+// RegisterLocaleTemplate registers template as the message for errors named
+// name in locale, replacing any previous one; an empty template removes it.
+// Locales are matched exactly, so register "fr" separately from "fr-CA" and
+// list both in RenderLocale to fall back between them. Thread-safe.
+// Example:
+//
+//	errors.RegisterLocaleTemplate("de", "NotFound", "Benutzer {user} nicht gefunden")
+func RegisterLocaleTemplate(locale, name, template string) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+	if template == "" {
+		delete(localeTemplates[locale], name)
+		return
+	}
+	if localeTemplates[locale] == nil {
+		localeTemplates[locale] = make(map[string]string)
+	}
+	localeTemplates[locale][name] = template
+}
+
+// localeTemplate returns the template registered for name in locale, if any.
+func localeTemplate(locale, name string) (string, bool) {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	tmpl, ok := localeTemplates[locale][name]
+	return tmpl, ok
+}
+
+// RenderLocale renders the error with the first template registered for its
+// name among locales, tried in order of preference, filling {key} placeholders
+// from the context as Render does. If no locale has one, or the error has no
+// name, it falls back to Render: the error's own template, then Error().
+// Example:
+//
+//	msg := err.RenderLocale("fr-CA", "fr", "en")
+func (e *Error) RenderLocale(locales ...string) string {
+	if e == nil {
+		return ""
+	}
+	if e.name != "" {
+		for _, locale := range locales {
+			if tmpl, ok := localeTemplate(locale, e.name); ok {
+				return e.fillTemplate(tmpl)
+			}
+		}
+	}
+	return e.Render()
+}
@@ -0,0 +1,43 @@
+package errors
+
+import "testing"
+
+func TestLocaleRenderFallback(t *testing.T) {
+	RegisterLocaleTemplate("fr", "LocaleNotFound", "utilisateur {user} introuvable")
+	RegisterLocaleTemplate("en", "LocaleNotFound", "user {user} not found")
+	RegisterLocaleTemplate("fr-CA", "LocaleOther", "autre")
+	defer func() {
+		RegisterLocaleTemplate("fr", "LocaleNotFound", "")
+		RegisterLocaleTemplate("en", "LocaleNotFound", "")
+		RegisterLocaleTemplate("fr-CA", "LocaleOther", "")
+	}()
+
+	err := Named("LocaleNotFound").Msgf("lookup failed").With("user", "alice")
+	defer err.Free()
+
+	tests := []struct {
+		locales []string
+		want    string
+	}{
+		{[]string{"fr-CA", "fr", "en"}, "utilisateur alice introuvable"},
+		{[]string{"de", "en"}, "user alice not found"},
+		{[]string{"de"}, "lookup failed"},
+		{nil, "lookup failed"},
+	}
+	for _, tt := range tests {
+		if got := err.RenderLocale(tt.locales...); got != tt.want {
+			t.Errorf("RenderLocale(%v) = %q, want %q", tt.locales, got, tt.want)
+		}
+	}
+
+	withTemplate := Named("LocaleNotFound").WithTemplate("default for {user}").With("user", "bob")
+	defer withTemplate.Free()
+	if got := withTemplate.RenderLocale("de"); got != "default for bob" {
+		t.Errorf("RenderLocale() should fall back to the error's template, got %q", got)
+	}
+
+	RegisterLocaleTemplate("fr", "LocaleNotFound", "")
+	if got := err.RenderLocale("fr", "en"); got != "user alice not found" {
+		t.Errorf("RenderLocale() after removal = %q, want the en template", got)
+	}
+}
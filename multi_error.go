@@ -22,6 +22,17 @@ type MultiError struct {
 	sampling   bool           // Whether sampling is enabled to limit error collection
 	sampleRate uint32         // Sampling percentage (1-100) when sampling is enabled
 	rand       *rand.Rand     // Random source for sampling (nil defaults to fastRand)
+
+	stripes []multiErrorStripe // Per-stripe pending errors when striped (nil = single mutex)
+	pending atomic.Int64       // Errors held in stripes awaiting the next merge
+}
+
+// multiErrorStripe holds errors added to a striped MultiError until the next read
+// merges them. Padded to a cache line so neighbouring stripes don't false-share.
+type multiErrorStripe struct {
+	mu      sync.Mutex
+	pending []error
+	_       [32]byte
 }
 
 // ErrorFormatter defines a function for custom error message formatting.
@@ -47,50 +58,102 @@ func NewMultiError(opts ...MultiErrorOption) *MultiError {
 
 // Add appends an error to the collection with optional sampling, limit checks, and duplicate prevention.
 // Ignores nil errors and duplicates based on string equality; thread-safe.
+// In striped mode (see WithStriped) the checks are deferred until the next read.
 func (m *MultiError) Add(errs ...error) {
 	if len(errs) == 0 {
 		return
 	}
+	if m.stripes != nil {
+		m.addStriped(errs)
+		return
+	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	for _, err := range errs {
-		if err == nil {
-			continue
-		}
+		m.addLocked(err)
+	}
+}
 
-		// Check for duplicates by comparing error messages
-		duplicate := false
-		for _, e := range m.errors {
-			if e.Error() == err.Error() {
-				duplicate = true
-				break
-			}
+// addLocked applies duplicate, sampling, and limit checks and appends err.
+// Caller must hold m.mu for writing.
+func (m *MultiError) addLocked(err error) {
+	if err == nil {
+		return
+	}
+
+	// Check for duplicates by comparing error messages
+	if containsMessage(m.errors, err) {
+		return
+	}
+
+	// Apply sampling if enabled and collection isn’t empty
+	if m.sampling && len(m.errors) > 0 {
+		var r uint32
+		if m.rand != nil {
+			r = uint32(m.rand.Int31n(100))
+		} else {
+			r = fastRand() % 100
 		}
-		if duplicate {
-			continue
+		if r > m.sampleRate { // Accept if random value is within sample rate
+			return
 		}
+	}
 
-		// Apply sampling if enabled and collection isn’t empty
-		if m.sampling && len(m.errors) > 0 {
-			var r uint32
-			if m.rand != nil {
-				r = uint32(m.rand.Int31n(100))
-			} else {
-				r = fastRand() % 100
-			}
-			if r > m.sampleRate { // Accept if random value is within sample rate
-				continue
-			}
-		}
+	// Respect limit if set
+	if m.limit > 0 && len(m.errors) >= m.limit {
+		return
+	}
 
-		// Respect limit if set
-		if m.limit > 0 && len(m.errors) >= m.limit {
-			continue
+	m.errors = append(m.errors, err)
+}
+
+// addStriped appends errs to a randomly chosen stripe without taking m.mu,
+// skipping duplicates already pending there so merges stay small.
+// The top-level math/rand source is lock-free, so picking a stripe is cheap.
+// With a limit set, once the stripes hold that many errors they are merged early,
+// and errs is dropped if the collection is full, so pending errors stay bounded.
+func (m *MultiError) addStriped(errs []error) {
+	if m.limit > 0 && m.pending.Load() >= int64(m.limit) {
+		m.collect()
+		m.mu.RLock()
+		full := len(m.errors) >= m.limit
+		m.mu.RUnlock()
+		if full {
+			return
 		}
+	}
+	s := &m.stripes[rand.Uint32()%uint32(len(m.stripes))]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, err := range errs {
+		if err != nil && !containsMessage(s.pending, err) {
+			s.pending = append(s.pending, err)
+			m.pending.Add(1)
+		}
+	}
+}
 
-		m.errors = append(m.errors, err)
+// collect merges pending stripe errors into m.errors, applying the usual Add checks.
+// Called at the start of every read; no-op unless striped and some errors are pending,
+// so reads don't take the write lock when nothing was added since the last merge.
+func (m *MultiError) collect() {
+	if m.stripes == nil || m.pending.Load() == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.stripes {
+		s := &m.stripes[i]
+		s.mu.Lock()
+		pending := s.pending
+		s.pending = nil
+		m.pending.Add(-int64(len(pending)))
+		s.mu.Unlock()
+		for _, err := range pending {
+			m.addLocked(err)
+		}
 	}
 }
 
@@ -104,12 +167,20 @@ func (m *MultiError) Addf(format string, args ...interface{}) {
 func (m *MultiError) Clear() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	for i := range m.stripes {
+		s := &m.stripes[i]
+		s.mu.Lock()
+		m.pending.Add(-int64(len(s.pending)))
+		s.pending = nil
+		s.mu.Unlock()
+	}
 	m.errors = m.errors[:0]
 }
 
 // Count returns the number of errors in the collection.
 // Thread-safe.
 func (m *MultiError) Count() int {
+	m.collect()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	return len(m.errors)
@@ -119,6 +190,7 @@ func (m *MultiError) Count() int {
 // Returns empty string if no errors, single error message if one exists,
 // or a formatted list using custom formatter or default if multiple; thread-safe.
 func (m *MultiError) Error() string {
+	m.collect()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -138,6 +210,7 @@ func (m *MultiError) Error() string {
 // Errors returns a copy of the contained errors.
 // Thread-safe; returns nil if no errors exist.
 func (m *MultiError) Errors() []error {
+	m.collect()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -152,6 +225,7 @@ func (m *MultiError) Errors() []error {
 // Filter returns a new MultiError containing only errors that match the predicate.
 // Thread-safe; preserves original configuration including limit, formatter, and sampling.
 func (m *MultiError) Filter(fn func(error) bool) *MultiError {
+	m.collect()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -163,6 +237,9 @@ func (m *MultiError) Filter(fn func(error) bool) *MultiError {
 	if m.sampling {
 		opts = append(opts, WithSampling(m.sampleRate))
 	}
+	if m.stripes != nil {
+		opts = append(opts, WithStriped(len(m.stripes)))
+	}
 
 	filtered := NewMultiError(opts...)
 	for _, err := range m.errors {
@@ -176,6 +253,7 @@ func (m *MultiError) Filter(fn func(error) bool) *MultiError {
 // First returns the first error in the collection, if any.
 // Thread-safe; returns nil if the collection is empty.
 func (m *MultiError) First() error {
+	m.collect()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	if len(m.errors) > 0 {
@@ -187,6 +265,7 @@ func (m *MultiError) First() error {
 // Has reports whether the collection contains any errors.
 // Thread-safe.
 func (m *MultiError) Has() bool {
+	m.collect()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	return len(m.errors) > 0
@@ -195,6 +274,7 @@ func (m *MultiError) Has() bool {
 // Last returns the most recently added error in the collection, if any.
 // Thread-safe; returns nil if the collection is empty.
 func (m *MultiError) Last() error {
+	m.collect()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	if len(m.errors) > 0 {
@@ -216,6 +296,7 @@ func (m *MultiError) Merge(other *MultiError) {
 	//      calling m.Add (which takes m.mu.Lock) deadlocks on the same mutex.
 	// Concurrent-write race: m had no lock protection during the loop,
 	//      so a concurrent Add on m could corrupt the slice.
+	other.collect()
 	other.mu.RLock()
 	snapshot := make([]error, len(other.errors))
 	copy(snapshot, other.errors)
@@ -224,9 +305,26 @@ func (m *MultiError) Merge(other *MultiError) {
 	m.Add(snapshot...)
 }
 
+// Range calls fn for each error in insertion order, stopping if fn returns false.
+// Iterates under the read lock without copying; fn must not modify m.
+func (m *MultiError) Range(fn func(i int, err error) bool) {
+	if fn == nil {
+		return
+	}
+	m.collect()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for i, err := range m.errors {
+		if !fn(i, err) {
+			return
+		}
+	}
+}
+
 // IsNull checks if the MultiError is empty or contains only null errors.
 // Returns true if empty or all errors are null (via IsNull() or empty message); thread-safe.
 func (m *MultiError) IsNull() bool {
+	m.collect()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -260,6 +358,7 @@ func (m *MultiError) IsNull() bool {
 // or the MultiError itself if multiple errors are present.
 // Thread-safe; useful for unwrapping to a single error when possible.
 func (m *MultiError) Single() error {
+	m.collect()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -324,9 +423,25 @@ func WithRand(r *rand.Rand) MultiErrorOption {
 	}
 }
 
+// WithStriped spreads Add across n independently locked stripes to reduce contention
+// when many goroutines add concurrently; reads merge the stripes first. Duplicate,
+// sampling, and limit checks run at merge time, and insertion order across
+// goroutines is not preserved. With WithLimit, the stripes are merged early once
+// they hold limit errors, so memory stays bounded at about twice the limit. Values of n below 2 keep the single-mutex mode.
+func WithStriped(n int) MultiErrorOption {
+	return func(m *MultiError) {
+		if n < 2 {
+			m.stripes = nil
+			return
+		}
+		m.stripes = make([]multiErrorStripe, n)
+	}
+}
+
 // MarshalJSON serializes the MultiError to JSON, including all contained errors and configuration metadata.
 // Thread-safe; errors are serialized using their MarshalJSON method if available, otherwise as strings.
 func (m *MultiError) MarshalJSON() ([]byte, error) {
+	m.collect()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -340,28 +455,47 @@ func (m *MultiError) MarshalJSON() ([]byte, error) {
 	enc.SetEscapeHTML(false)
 
 	// Define JSON structure
-	type jsonError struct {
-		Error interface{} `json:"error"` // Holds either JSON-marshaled error or string
-	}
-
 	je := struct {
-		Count      int         `json:"count"`                 // Number of errors
-		Limit      int         `json:"limit,omitempty"`       // Maximum error limit (omitted if 0)
-		Sampling   bool        `json:"sampling,omitempty"`    // Whether sampling is enabled
-		SampleRate uint32      `json:"sample_rate,omitempty"` // Sampling rate (1-100, omitted if not sampling)
-		Errors     []jsonError `json:"errors"`                // List of errors
+		Count      int              `json:"count"`                 // Number of errors
+		Limit      int              `json:"limit,omitempty"`       // Maximum error limit (omitted if 0)
+		Sampling   bool             `json:"sampling,omitempty"`    // Whether sampling is enabled
+		SampleRate uint32           `json:"sample_rate,omitempty"` // Sampling rate (1-100, omitted if not sampling)
+		Errors     []multiJSONError `json:"errors"`                // List of errors
 	}{
 		Count:      len(m.errors),
 		Limit:      m.limit,
 		Sampling:   m.sampling,
 		SampleRate: m.sampleRate,
+		Errors:     multiJSONErrors(m.errors),
 	}
 
-	// Serialize each error
-	je.Errors = make([]jsonError, len(m.errors))
-	for i, err := range m.errors {
+	// Encode JSON
+	if err := enc.Encode(je); err != nil {
+		return nil, fmt.Errorf("failed to marshal MultiError: %v", err)
+	}
+
+	// Copy out of buf's backing array before returning buf to pool.
+	raw := buf.Bytes()
+	if len(raw) > 0 && raw[len(raw)-1] == '\n' {
+		raw = raw[:len(raw)-1]
+	}
+	result := make([]byte, len(raw))
+	copy(result, raw)
+	jsonBufferPool.Put(buf)
+	return result, nil
+}
+
+// multiJSONError is the JSON shape of one error in a MultiError.
+type multiJSONError struct {
+	Error interface{} `json:"error"` // Holds either JSON-marshaled error or string
+}
+
+// multiJSONErrors converts errs for JSON encoding, using each error's
+// MarshalJSON method if available and its message otherwise.
+func multiJSONErrors(errs []error) []multiJSONError {
+	out := make([]multiJSONError, len(errs))
+	for i, err := range errs {
 		if err == nil {
-			je.Errors[i] = jsonError{Error: nil}
 			continue
 		}
 		// Check if the error implements json.Marshaler
@@ -370,31 +504,90 @@ func (m *MultiError) MarshalJSON() ([]byte, error) {
 			marshaled, marshalErr := marshaler.MarshalJSON()
 			if marshalErr != nil {
 				// Fallback reports the ORIGINAL error message, not the marshal failure.
-				je.Errors[i] = jsonError{Error: err.Error()}
+				out[i] = multiJSONError{Error: err.Error()}
 			} else {
-				var raw json.RawMessage = marshaled
-				je.Errors[i] = jsonError{Error: raw}
+				out[i] = multiJSONError{Error: json.RawMessage(marshaled)}
 			}
 		} else {
 			// Use error string for non-marshaler errors
-			je.Errors[i] = jsonError{Error: err.Error()}
+			out[i] = multiJSONError{Error: err.Error()}
 		}
 	}
+	return out
+}
 
-	// Encode JSON
-	if err := enc.Encode(je); err != nil {
-		return nil, fmt.Errorf("failed to marshal MultiError: %v", err)
+// multiFormats holds the named formatters used by MultiError.Render.
+// multiFormatsMu guards it against concurrent RegisterMultiFormat calls.
+var (
+	multiFormatsMu sync.RWMutex
+	multiFormats   = map[string]ErrorFormatter{
+		"text":     defaultFormat,
+		"json":     jsonFormat,
+		"markdown": markdownFormat,
 	}
+)
 
-	// Copy out of buf's backing array before returning buf to pool.
-	raw := buf.Bytes()
-	if len(raw) > 0 && raw[len(raw)-1] == '\n' {
-		raw = raw[:len(raw)-1]
+// RegisterMultiFormat registers f under name for use with MultiError.Render,
+// replacing any formatter of that name, including the built-in "text", "json",
+// and "markdown". A nil f removes the format. Thread-safe.
+// Example:
+//
+//	errors.RegisterMultiFormat("csv", func(errs []error) string { ... })
+func RegisterMultiFormat(name string, f ErrorFormatter) {
+	multiFormatsMu.Lock()
+	defer multiFormatsMu.Unlock()
+	if f == nil {
+		delete(multiFormats, name)
+		return
 	}
-	result := make([]byte, len(raw))
-	copy(result, raw)
-	jsonBufferPool.Put(buf)
-	return result, nil
+	multiFormats[name] = f
+}
+
+// Render formats the collection with the formatter registered under format,
+// so one collection can be rendered for several sinks: "text" (as Error()
+// with several errors), "json" (the count and errors as in MarshalJSON), and
+// "markdown" (a bulleted list) are built in; see RegisterMultiFormat. Unlike
+// Error, the formatter is used for any number of errors. An unknown format
+// falls back to Error(). Thread-safe.
+// Example:
+//
+//	fmt.Println(m.Render("text"))
+//	report.WriteString(m.Render("markdown"))
+func (m *MultiError) Render(format string) string {
+	multiFormatsMu.RLock()
+	f := multiFormats[format]
+	multiFormatsMu.RUnlock()
+	if f == nil {
+		return m.Error()
+	}
+	return f(m.Errors())
+}
+
+// jsonFormat renders errs as a JSON object with their count and the errors,
+// in the shape of MultiError.MarshalJSON without configuration fields.
+func jsonFormat(errs []error) string {
+	data, err := json.Marshal(struct {
+		Count  int              `json:"count"`
+		Errors []multiJSONError `json:"errors"`
+	}{len(errs), multiJSONErrors(errs)})
+	if err != nil {
+		return defaultFormat(errs)
+	}
+	return string(data)
+}
+
+// markdownFormat renders errs as a Markdown bulleted list, one error per line;
+// newlines within messages are replaced by spaces to keep each item on one line.
+func markdownFormat(errs []error) string {
+	var sb strings.Builder
+	for i, err := range errs {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString("- ")
+		sb.WriteString(strings.ReplaceAll(err.Error(), "\n", " "))
+	}
+	return sb.String()
 }
 
 // defaultFormat provides the default formatting for multiple errors.
@@ -411,6 +604,17 @@ func defaultFormat(errs []error) string {
 	return sb.String()
 }
 
+// containsMessage reports whether errs holds an error with the same message as err.
+func containsMessage(errs []error, err error) bool {
+	msg := err.Error()
+	for _, e := range errs {
+		if e.Error() == msg {
+			return true
+		}
+	}
+	return false
+}
+
 // fastRand generates a quick pseudo-random number for sampling.
 // Uses a simple xorshift algorithm based on the current time; not cryptographically secure.
 var fastRandState uint32 = 1 // Must be non-zero
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -115,6 +116,89 @@ func TestMultiError_Filter(t *testing.T) {
 	}
 }
 
+// TestMultiError_Range tests in-place iteration with Range.
+// Sums codes across all errors and verifies early termination when fn returns false.
+func TestMultiError_Range(t *testing.T) {
+	m := NewMultiError()
+	m.Add(New("bad input").WithCode(400), errors.New("plain"), New("missing").WithCode(404))
+
+	sum := 0
+	var indices []int
+	m.Range(func(i int, err error) bool {
+		indices = append(indices, i)
+		sum += Code(err)
+		return true
+	})
+	if sum != 400+DefaultCode+404 {
+		t.Errorf("Expected code sum %d, got %d", 400+DefaultCode+404, sum)
+	}
+	if !reflect.DeepEqual(indices, []int{0, 1, 2}) {
+		t.Errorf("Expected indices [0 1 2], got %v", indices)
+	}
+
+	visited := 0
+	m.Range(func(i int, err error) bool {
+		visited++
+		return i < 1
+	})
+	if visited != 2 {
+		t.Errorf("Range should stop when fn returns false, visited %d", visited)
+	}
+
+	m.Range(nil) // Must not panic
+}
+
+// TestMultiError_Striped tests concurrent accumulation with WithStriped.
+// Verifies reads merge all stripes and that duplicate and limit checks still apply.
+func TestMultiError_Striped(t *testing.T) {
+	m := NewMultiError(WithStriped(4))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				m.Add(fmt.Errorf("g%d-%d", g, i), errors.New("shared"))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if m.Count() != 8*50+1 {
+		t.Errorf("Expected %d unique errors, got %d", 8*50+1, m.Count())
+	}
+	if len(m.Errors()) != m.Count() {
+		t.Errorf("Errors() length %d does not match Count() %d", len(m.Errors()), m.Count())
+	}
+
+	limited := NewMultiError(WithStriped(4), WithLimit(10))
+	for i := 0; i < 100; i++ {
+		limited.Add(fmt.Errorf("err%d", i))
+	}
+	if held := limited.pending.Load(); held > 10 {
+		t.Errorf("Stripes should hold at most the limit before merging, got %d", held)
+	}
+	if limited.Count() != 10 {
+		t.Errorf("Should cap at 10 errors, got %d", limited.Count())
+	}
+	if filtered := limited.Filter(func(error) bool { return true }); filtered.stripes == nil {
+		t.Error("Filter should preserve striped mode")
+	}
+
+	limited.Add(errors.New("pending"))
+	limited.Clear()
+	if limited.Has() {
+		t.Errorf("Clear should drop merged and pending errors, got %d", limited.Count())
+	}
+	if held := limited.pending.Load(); held != 0 {
+		t.Errorf("Clear should reset the pending count, got %d", held)
+	}
+
+	if NewMultiError(WithStriped(1)).stripes != nil {
+		t.Error("WithStriped(1) should keep single-mutex mode")
+	}
+}
+
 // TestMultiError_AsSingle tests the Single() method across different scenarios.
 // Verifies behavior for empty, single-error, and multi-error cases.
 func TestMultiError_AsSingle(t *testing.T) {
@@ -314,3 +398,82 @@ func TestMultiError_MarshalJSON(t *testing.T) {
 		}
 	})
 }
+
+// TestMultiError_Render tests rendering one collection in several named formats.
+func TestMultiError_Render(t *testing.T) {
+	m := NewMultiError()
+	m.Add(errors.New("disk full"), New("quota exceeded").WithCode(429))
+
+	if got, want := m.Render("text"), "errors(2): disk full; quota exceeded"; got != want {
+		t.Errorf("Render(text) = %q, want %q", got, want)
+	}
+	if got, want := m.Render("markdown"), "- disk full\n- quota exceeded"; got != want {
+		t.Errorf("Render(markdown) = %q, want %q", got, want)
+	}
+
+	var actual, expected interface{}
+	if err := json.Unmarshal([]byte(m.Render("json")), &actual); err != nil {
+		t.Fatalf("Render(json) is not valid JSON: %v", err)
+	}
+	_ = json.Unmarshal([]byte(`{"count":2,"errors":[{"error":"disk full"},{"error":{"message":"quota exceeded","code":429}}]}`), &expected)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Render(json) = %s", m.Render("json"))
+	}
+
+	RegisterMultiFormat("count", func(errs []error) string { return fmt.Sprint(len(errs)) })
+	defer RegisterMultiFormat("count", nil)
+	if got := m.Render("count"); got != "2" {
+		t.Errorf("Render(count) = %q, want %q", got, "2")
+	}
+	if got := m.Render("unknown"); got != m.Error() {
+		t.Errorf("Render(unknown) = %q, want Error() %q", got, m.Error())
+	}
+}
+
+// TestMultiError_FilterByTag tests filtering a collection by error tags.
+func TestMultiError_FilterByTag(t *testing.T) {
+	m := NewMultiError()
+	m.Add(
+		New("card declined").WithTags("payment", "stripe"),
+		New("disk full").WithTags("storage"),
+		Wrapf(New("refund failed").WithTags("payment"), "request %d", 7),
+		errors.New("plain"),
+	)
+
+	payments := m.Filter(func(err error) bool { return HasTag(err, "payment") })
+	if payments.Count() != 2 {
+		t.Fatalf("Expected 2 payment errors, got %d: %v", payments.Count(), payments)
+	}
+	if payments.First().Error() != "card declined" {
+		t.Errorf("Expected first payment error 'card declined', got %q", payments.First())
+	}
+}
+
+// TestMultiError_As tests extracting a wrapped MultiError with As.
+func TestMultiError_As(t *testing.T) {
+	m := NewMultiError()
+	m.Add(New("a"), New("b"))
+
+	cases := map[string]error{
+		"direct":    m,
+		"wrapped":   New("batch failed").Wrap(m),
+		"named":     Named("BatchError").Wrap(New("step").Wrap(m)),
+		"fmt":       fmt.Errorf("outer: %w", New("batch failed").Wrap(m)),
+		"stdlibTop": fmt.Errorf("outer: %w", m),
+	}
+	for name, err := range cases {
+		var got *MultiError
+		if !As(err, &got) || got != m {
+			t.Errorf("%s: As(err, &multi) = %v, want the wrapped MultiError", name, got)
+		}
+		got = nil
+		if !errors.As(err, &got) || got != m {
+			t.Errorf("%s: errors.As(err, &multi) = %v, want the wrapped MultiError", name, got)
+		}
+	}
+
+	var got *MultiError
+	if As(New("plain"), &got) || got != nil {
+		t.Errorf("As() without a MultiError in the chain = %v, want false", got)
+	}
+}
@@ -3,6 +3,15 @@ package errors
 import (
 	"sync"
 	"sync/atomic"
+	"time"
+)
+
+// Tuning for AutoWarmPool.
+const (
+	autoWarmInterval = 100 * time.Millisecond // Time between miss-rate samples
+	autoWarmRounds   = 50                     // Maximum samples before the tuner stops
+	autoWarmMissRate = 0.10                   // Miss rate below which warming stops
+	autoWarmMax      = 10000                  // Maximum instances AutoWarmPool adds in total
 )
 
 // ErrorPool is a high-performance, thread-safe pool for reusing *Error instances.
@@ -13,20 +22,14 @@ type ErrorPool struct {
 		hits   atomic.Int64 // Number of times an error was reused from the pool
 		misses atomic.Int64 // Number of times a new error was created due to pool miss
 	}
+	size atomic.Int64 // Approximate number of errors held, for Config.MaxPoolSize
 }
 
 // NewErrorPool creates a new ErrorPool instance.
-// Initializes the pool with a New function that returns a fresh *Error with default smallContext.
+// The underlying pool has no New function, so Get can tell reuse from allocation
+// and Stats reports accurate misses.
 func NewErrorPool() *ErrorPool {
-	return &ErrorPool{
-		pool: sync.Pool{
-			New: func() interface{} {
-				return &Error{
-					smallContext: [contextSize]contextItem{},
-				}
-			},
-		},
-	}
+	return &ErrorPool{}
 }
 
 // Get retrieves an *Error from the pool or creates a new one if pooling is disabled or pool is empty.
@@ -38,9 +41,10 @@ func (ep *ErrorPool) Get() *Error {
 		}
 	}
 
-	e := ep.pool.Get().(*Error)
-	if e == nil { // Pool returned nil (unlikely due to New func, but handled for safety)
+	e, _ := ep.pool.Get().(*Error)
+	if e == nil { // Pool is empty: allocate and count a miss
 		ep.poolStats.misses.Add(1)
+		ep.size.Store(0) // Resync after the GC drops pooled instances
 		e = &Error{
 			smallContext: [contextSize]contextItem{},
 		}
@@ -48,6 +52,7 @@ func (ep *ErrorPool) Get() *Error {
 		return e
 	}
 	ep.poolStats.hits.Add(1)
+	ep.size.Add(-1)
 	// Register auto-cleanup so GC can return the error to the pool if the
 	// caller forgets to call Free(). If AutoFree is false this is a no-op.
 	ep.setupCleanup(e)
@@ -56,10 +61,14 @@ func (ep *ErrorPool) Get() *Error {
 
 // Put returns an *Error to the pool after resetting it.
 // Ignores nil errors or if pooling is disabled; preserves stack capacity; thread-safe.
+// Once the pool holds Config.MaxPoolSize errors, further ones are left to the GC.
 func (ep *ErrorPool) Put(e *Error) {
 	if e == nil || currentConfig.disablePooling {
 		return
 	}
+	if limit := int64(currentConfig.maxPoolSize); limit > 0 && ep.size.Load() >= limit {
+		return
+	}
 
 	// Reset the error to a clean state, preserving capacity
 	e.Reset()
@@ -69,6 +78,7 @@ func (ep *ErrorPool) Put(e *Error) {
 		e.stack = e.stack[:0]
 	}
 
+	ep.size.Add(1)
 	ep.pool.Put(e)
 }
 
@@ -77,3 +87,71 @@ func (ep *ErrorPool) Put(e *Error) {
 func (ep *ErrorPool) Stats() (hits, misses int64) {
 	return ep.poolStats.hits.Load(), ep.poolStats.misses.Load()
 }
+
+// Size returns the approximate number of errors held by the pool. It may
+// overcount after the GC drops pooled instances until the next miss resyncs it.
+func (ep *ErrorPool) Size() int64 {
+	return ep.size.Load()
+}
+
+// AutoWarmPool starts a background tuner that samples the error pool's miss rate
+// every 100ms for up to five seconds. While more than 10% of Gets in a sample
+// miss, it warms the pool by the number of misses seen, adding at most 10000
+// instances in total. Call it at startup, alongside real traffic, instead of
+// guessing a WarmPool count. No-op if pooling is disabled.
+// Example:
+//
+//	errors.AutoWarmPool()
+func AutoWarmPool() {
+	if currentConfig.disablePooling {
+		return
+	}
+	t := newPoolTuner(errorPool, WarmPool)
+	go func() {
+		ticker := time.NewTicker(autoWarmInterval)
+		defer ticker.Stop()
+		for i := 0; i < autoWarmRounds; i++ {
+			<-ticker.C
+			if !t.step() {
+				return
+			}
+		}
+	}()
+}
+
+// poolTuner grows an ErrorPool in response to observed misses; see AutoWarmPool.
+type poolTuner struct {
+	pool       *ErrorPool
+	warm       func(n int) // Adds n instances to pool
+	lastHits   int64
+	lastMisses int64
+	warmed     int // Instances added so far
+}
+
+// newPoolTuner creates a tuner that measures misses from the pool's current stats.
+func newPoolTuner(pool *ErrorPool, warm func(n int)) *poolTuner {
+	t := &poolTuner{pool: pool, warm: warm}
+	t.lastHits, t.lastMisses = pool.Stats()
+	return t
+}
+
+// step samples pool stats since the previous call and warms the pool by the
+// observed misses if the miss rate is above autoWarmMissRate. Reports whether
+// tuning should continue: false once a busy sample has few misses or the cap is hit.
+func (t *poolTuner) step() bool {
+	hits, misses := t.pool.Stats()
+	gets, missed := (hits-t.lastHits)+(misses-t.lastMisses), misses-t.lastMisses
+	t.lastHits, t.lastMisses = hits, misses
+	if gets == 0 {
+		return true // No traffic yet; keep waiting
+	}
+	if float64(missed)/float64(gets) < autoWarmMissRate {
+		return false
+	}
+	n := min(int(missed), autoWarmMax-t.warmed)
+	if n > 0 {
+		t.warm(n)
+		t.warmed += n
+	}
+	return t.warmed < autoWarmMax
+}
@@ -57,6 +57,7 @@ type Retry struct {
 	onRetry     func(int, error) // Callback executed after each failed attempt
 	backoff     BackoffStrategy  // Strategy for calculating retry delays
 	jitter      bool             // Whether to add random jitter to delays
+	rand        *rand.Rand       // Random source for jitter (nil uses the top-level math/rand source)
 	ctx         context.Context  // Context for cancellation and deadlines
 }
 
@@ -85,10 +86,21 @@ func NewRetry(options ...RetryOption) *Retry {
 }
 
 // addJitter adds ±25% jitter to avoid thundering herd problems.
-// Returns a duration adjusted by a random value between -25% and +25% of the input; not thread-safe.
-func addJitter(d time.Duration) time.Duration {
-	jitter := time.Duration(rand.Int63n(int64(d/2))) - (d / 4)
-	return d + jitter
+// Returns a duration adjusted by a random value between -25% and +25% of the input;
+// draws from src, or from the top-level math/rand source if src is nil. That
+// source is seeded randomly and, unless rand.Seed is called, safe for concurrent
+// use without a shared lock, so no package-local source is kept.
+func addJitter(d time.Duration, src *rand.Rand) time.Duration {
+	if d/2 <= 0 {
+		return d
+	}
+	var n int64
+	if src != nil {
+		n = src.Int63n(int64(d / 2))
+	} else {
+		n = rand.Int63n(int64(d / 2))
+	}
+	return d + time.Duration(n) - (d / 4)
 }
 
 // Attempts returns the configured maximum number of retry attempts.
@@ -137,7 +149,7 @@ func (r *Retry) Execute(fn func() error) error {
 			delay = r.maxDelay
 		}
 		if r.jitter {
-			delay = addJitter(delay)
+			delay = addJitter(delay, r.rand)
 		}
 
 		// Wait with context
@@ -151,9 +163,45 @@ func (r *Retry) Execute(fn func() error) error {
 	return lastErr
 }
 
+// attemptKey is the context key under which ExecuteCtx stores the attempt number.
+type attemptKey struct{}
+
+// WithAttempt returns a copy of ctx carrying the given 1-based attempt number,
+// as ExecuteCtx passes to each attempt. Useful for testing attempt-aware code.
+func WithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// AttemptFromContext returns the 1-based attempt number stored by ExecuteCtx or
+// WithAttempt, or 0 if ctx carries none.
+// Example:
+//
+//	if errors.AttemptFromContext(ctx) == retry.Attempts() {
+//	  log.Println("final attempt")
+//	}
+func AttemptFromContext(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
+
 // ExecuteContext runs the provided function with retry logic, respecting context cancellation.
 // Returns nil on success or the last error if all attempts fail or context is cancelled.
 func (r *Retry) ExecuteContext(ctx context.Context, fn func() error) error {
+	return r.ExecuteCtx(ctx, func(context.Context) error { return fn() })
+}
+
+// ExecuteCtx is like ExecuteContext but passes fn a context carrying the current
+// attempt number, retrievable with AttemptFromContext, so fn can vary its
+// behavior by attempt (e.g. log differently on the final try).
+// Example:
+//
+//	err := retry.ExecuteCtx(ctx, func(ctx context.Context) error {
+//	  return fetch(ctx, errors.AttemptFromContext(ctx))
+//	})
+func (r *Retry) ExecuteCtx(ctx context.Context, fn func(context.Context) error) error {
 	var lastErr error
 
 	// If the retry instance already has a context, use it. Otherwise, use the provided one.
@@ -176,7 +224,7 @@ func (r *Retry) ExecuteContext(ctx context.Context, fn func() error) error {
 			// Context is okay, proceed
 		}
 
-		err := fn()
+		err := fn(WithAttempt(execCtx, attempt))
 		if err == nil {
 			return nil // Success
 		}
@@ -204,7 +252,7 @@ func (r *Retry) ExecuteContext(ctx context.Context, fn func() error) error {
 			currentDelay = r.maxDelay
 		}
 		if r.jitter {
-			currentDelay = addJitter(currentDelay)
+			currentDelay = addJitter(currentDelay, r.rand)
 		}
 		if currentDelay < 0 { // Ensure delay isn't negative after jitter
 			currentDelay = 0
@@ -235,6 +283,7 @@ func (r *Retry) Transform(opts ...RetryOption) *Retry {
 		onRetry:     r.onRetry,
 		backoff:     r.backoff,
 		jitter:      r.jitter,
+		rand:        r.rand,
 		ctx:         r.ctx,
 	}
 	for _, opt := range opts {
@@ -282,6 +331,17 @@ func WithJitter(jitter bool) RetryOption {
 	}
 }
 
+// RetryWithRand sets a custom random source for jitter, useful for testing.
+// Returns a RetryOption; nil uses the top-level math/rand source. A *rand.Rand
+// is not safe for concurrent use and is read without locking, so give each
+// Retry its own source and don't execute that Retry, or Retries derived from
+// it with Transform, from several goroutines at once.
+func RetryWithRand(r *rand.Rand) RetryOption {
+	return func(retry *Retry) {
+		retry.rand = r
+	}
+}
+
 // WithMaxAttempts sets the maximum number of retry attempts.
 // Returns a RetryOption; ensures at least 1 attempt by adjusting lower values.
 func WithMaxAttempts(maxAttempts int) RetryOption {
@@ -354,7 +414,7 @@ func ExecuteReply[T any](r *Retry, fn func() (T, error)) (T, error) {
 			currentDelay = r.maxDelay
 		}
 		if r.jitter {
-			currentDelay = addJitter(currentDelay)
+			currentDelay = addJitter(currentDelay, r.rand)
 		}
 
 		// Wait with respect to context cancellation or timeout
@@ -144,3 +144,68 @@ func TestExecuteReply_DifferentTypes(t *testing.T) {
 		t.Errorf("Expected 2 calls, got %d", calls)
 	}
 }
+
+// TestRetryWithRand verifies that a seeded random source makes jitter reproducible.
+func TestRetryWithRand(t *testing.T) {
+	base := 100 * time.Millisecond
+	// Seed 1 draws a jitter near +25%, far from what an unseeded run would
+	// usually produce; the expected delay is derived from an identical source.
+	want := base - base/4 + time.Duration(rand.New(rand.NewSource(1)).Int63n(int64(base/2)))
+	if got := addJitter(base, rand.New(rand.NewSource(1))); got != want {
+		t.Errorf("addJitter() with seed 1 = %v, want %v", got, want)
+	}
+
+	r1 := NewRetry(
+		RetryWithRand(rand.New(rand.NewSource(1))),
+		WithMaxAttempts(2),
+		WithDelay(base),
+		WithBackoff(ConstantBackoff{}),
+		WithRetryIf(func(error) bool { return true }),
+	)
+
+	var attempts []time.Time
+	_ = r1.Execute(func() error {
+		attempts = append(attempts, time.Now())
+		return New("temporary error")
+	})
+	if len(attempts) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(attempts))
+	}
+	// Only a lower bound: the scheduler can add any amount of delay on top.
+	if got := attempts[1].Sub(attempts[0]); got < want {
+		t.Errorf("Delay between attempts = %v, want at least the seeded %v", got, want)
+	}
+
+	if got := r1.Transform(WithJitter(false)).rand; got != r1.rand {
+		t.Error("Transform should preserve the random source")
+	}
+}
+
+// TestRetryExecuteCtxAttempt verifies that ExecuteCtx exposes the attempt number
+// through the context passed to each attempt.
+func TestRetryExecuteCtxAttempt(t *testing.T) {
+	retry := NewRetry(
+		WithMaxAttempts(3),
+		WithDelay(time.Millisecond),
+		WithJitter(false),
+	)
+	var seen []int
+	err := retry.ExecuteCtx(context.Background(), func(ctx context.Context) error {
+		attempt := AttemptFromContext(ctx)
+		seen = append(seen, attempt)
+		if attempt < retry.Attempts() {
+			return New("temporary error").WithRetryable()
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Expected success on the final attempt, got %v", err)
+	}
+	if len(seen) != 3 || seen[0] != 1 || seen[1] != 2 || seen[2] != 3 {
+		t.Errorf("Expected attempts [1 2 3], got %v", seen)
+	}
+	if got := AttemptFromContext(context.Background()); got != 0 {
+		t.Errorf("Expected 0 outside ExecuteCtx, got %d", got)
+	}
+}
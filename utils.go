@@ -1,11 +1,15 @@
 package errors
 
 import (
+	"bytes"
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"runtime"
 	"strings"
+	"unicode/utf8"
 )
 
 // captureStack captures a stack trace with the configured depth.
@@ -76,6 +80,97 @@ func sqlNull(v interface{}) bool {
 	}
 }
 
+// goroutineID returns the current goroutine's ID, parsed from the header line
+// of runtime.Stack ("goroutine 42 [running]:"), or 0 if it cannot be parsed.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	var id uint64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	return id
+}
+
+// truncateContextValues returns ctx with string-like values longer than limit
+// bytes cut to limit and marked with truncatedMarker. ctx is copied before the
+// first change and returned as is when nothing needs cutting or limit <= 0.
+func truncateContextValues(ctx map[string]interface{}, limit int) map[string]interface{} {
+	if limit <= 0 {
+		return ctx
+	}
+	out, copied := ctx, false
+	for k, v := range ctx {
+		var s string
+		switch x := v.(type) {
+		case string:
+			s = x
+		case []byte:
+			s = string(x)
+		case error:
+			s = x.Error()
+		case fmt.Stringer:
+			s = x.String()
+		default:
+			continue
+		}
+		if len(s) <= limit {
+			continue
+		}
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if !copied {
+			out = make(map[string]interface{}, len(ctx))
+			for key, val := range ctx {
+				out[key] = val
+			}
+			copied = true
+		}
+		out[k] = s[:cut] + truncatedMarker
+	}
+	return out
+}
+
+// chainDepth returns the number of errors in err’s Unwrap chain, err included,
+// counting at most limit so long or cyclic chains stop early.
+func chainDepth(err error, limit int) int {
+	depth := 0
+	for ; err != nil && depth < limit; err = errors.Unwrap(err) {
+		depth++
+	}
+	return depth
+}
+
+// scrubString replaces every match of patterns in s with scrubbedMarker.
+func scrubString(s string, patterns []*regexp.Regexp) string {
+	for _, re := range patterns {
+		s = re.ReplaceAllLiteralString(s, scrubbedMarker)
+	}
+	return s
+}
+
+// scrubValue returns v's text with patterns replaced if any matched, and v
+// unchanged otherwise, so untouched values keep their type.
+func scrubValue(v interface{}, patterns []*regexp.Regexp) interface{} {
+	if v == nil {
+		return nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		s = fmt.Sprint(v)
+	}
+	if scrubbed := scrubString(s, patterns); scrubbed != s {
+		return scrubbed
+	}
+	return v
+}
+
 // getFuncName extracts the function name from an interface value.
 // Returns "unknown" if the input is nil or invalid.
 func getFuncName(fn interface{}) string {
@@ -86,6 +181,82 @@ func getFuncName(fn interface{}) string {
 	return strings.TrimPrefix(fullName, ".")
 }
 
+// cmpDesc compares a and b in descending order, returning -1 if a > b.
+func cmpDesc(a, b int) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	}
+	return 0
+}
+
+// stackFrom returns the stack of the innermost error in err’s chain that has
+// one, either as program counters or, for *Error with SetStackStrings, as
+// pre-rendered frames. The slices are copies.
+func stackFrom(err error) (pcs []uintptr, frames []string) {
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		switch x := current.(type) {
+		case *Error:
+			if len(x.stackStrings) > 0 {
+				pcs, frames = nil, append([]string(nil), x.stackStrings...)
+			} else if len(x.stack) > 0 {
+				pcs, frames = append([]uintptr(nil), x.stack...), nil
+			}
+		case interface{ Callers() []uintptr }:
+			if c := x.Callers(); len(c) > 0 {
+				pcs, frames = append([]uintptr(nil), c...), nil
+			}
+		default:
+			if c := stackTracePCs(current); len(c) > 0 {
+				pcs, frames = c, nil
+			}
+		}
+	}
+	return pcs, frames
+}
+
+// stackTracePCs calls a pkg/errors-style StackTrace method, whose result is a
+// slice of a uintptr-based Frame type, and returns the program counters.
+// Reflection avoids depending on the package that defines the types.
+func stackTracePCs(err error) []uintptr {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+	out := m.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	trace := m.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	return pcs
+}
+
+// frameFilter returns the configured predicate deciding which frames Stack and
+// FastStack keep: Config.StackFilter if set, otherwise one dropping internal
+// frames when FilterInternal is on. Returns nil when every frame is kept.
+func frameFilter() func(runtime.Frame) bool {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	if currentConfig.stackFilter != nil {
+		return currentConfig.stackFilter
+	}
+	if currentConfig.filterInternal {
+		return keepExternalFrame
+	}
+	return nil
+}
+
+// keepExternalFrame is the default frame filter used when FilterInternal is on.
+func keepExternalFrame(frame runtime.Frame) bool {
+	return !isInternalFrame(frame)
+}
+
 // isInternalFrame reports whether a stack frame belongs to this library's
 // internals and should be filtered from user-visible stack traces.
 //